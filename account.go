@@ -0,0 +1,212 @@
+package signicat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// AccountService handles communication with the Account API. Used to manage the account, its sub-accounts and their branding.
+type AccountService service
+
+// RetrieveAccount retrieves the account the client is authenticated as.
+func (s *AccountService) RetrieveAccount(ctx context.Context) (*Account, error) {
+	req, err := s.client.NewRequest(http.MethodGet, "/admin/account", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(Account)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// UpdateAccount updates the account the client is authenticated as. Only fields set in the request are updated.
+func (s *AccountService) UpdateAccount(ctx context.Context, updateReq *UpdateAccountRequest) (*Account, error) {
+	req, err := s.client.NewRequest(http.MethodPatch, "/admin/account", updateReq)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(Account)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// CreateSubAccount creates a new account under the account the client is authenticated as.
+func (s *AccountService) CreateSubAccount(ctx context.Context, createReq *CreateAccountRequest) (*Account, error) {
+	req, err := s.client.NewRequest(http.MethodPost, "/admin/accounts", createReq)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(Account)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ListSubAccounts lists all accounts under the account the client is authenticated as.
+func (s *AccountService) ListSubAccounts(ctx context.Context) ([]*Account, error) {
+	req, err := s.client.NewRequest(http.MethodGet, "/admin/accounts", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []*Account
+	if err := s.client.Do(ctx, req, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// RetrieveSubAccount retrieves a single sub-account.
+func (s *AccountService) RetrieveSubAccount(ctx context.Context, accountID string) (*Account, error) {
+	u := fmt.Sprintf("/admin/accounts/%s", accountID)
+	req, err := s.client.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(Account)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// RetrieveDealer retrieves the dealer the account the client is authenticated as belongs to.
+func (s *AccountService) RetrieveDealer(ctx context.Context) (*Dealer, error) {
+	req, err := s.client.NewRequest(http.MethodGet, "/admin/dealer", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(Dealer)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// RetrieveBranding retrieves the branding defaults for an account. The defaults are used when a request doesn't specify its own
+// styling.
+func (s *AccountService) RetrieveBranding(ctx context.Context, accountID string) (*Branding, error) {
+	u := fmt.Sprintf("/admin/accounts/%s/branding", accountID)
+	req, err := s.client.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(Branding)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// UpdateBranding replaces the branding defaults for an account.
+func (s *AccountService) UpdateBranding(ctx context.Context, accountID string, branding *Branding) (*Branding, error) {
+	u := fmt.Sprintf("/admin/accounts/%s/branding", accountID)
+	req, err := s.client.NewRequest(http.MethodPut, u, branding)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(Branding)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// CreateAccountRequest is the request body used to create a sub-account.
+type CreateAccountRequest struct {
+	Name              string            `json:"name"`
+	ExternalReference string            `json:"externalReference,omitempty"`
+	Organization      *Organization     `json:"organization"`
+	Contact           *AccountContact   `json:"contact,omitempty"`
+	Address           *Address          `json:"address,omitempty"`
+	Branding          *Branding         `json:"branding,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+}
+
+// UpdateAccountRequest is the request body used to update an account. Fields left empty are not updated.
+type UpdateAccountRequest struct {
+	Name              string            `json:"name,omitempty"`
+	ExternalReference string            `json:"externalReference,omitempty"`
+	Organization      *Organization     `json:"organization,omitempty"`
+	Contact           *AccountContact   `json:"contact,omitempty"`
+	Address           *Address          `json:"address,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+}
+
+// Account is a Signicat account.
+type Account struct {
+	ID                string            `json:"id,omitempty"`
+	Name              string            `json:"name,omitempty"`
+	ExternalReference string            `json:"externalReference,omitempty"`
+	ParentAccountID   string            `json:"parentAccountId,omitempty"`
+	DealerID          string            `json:"dealerId,omitempty"`
+	Enabled           bool              `json:"enabled,omitempty"`
+	Organization      *Organization     `json:"organization,omitempty"`
+	Contact           *AccountContact   `json:"contact,omitempty"`
+	Address           *Address          `json:"address,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	Created           *time.Time        `json:"created,omitempty"`
+	LastModified      *time.Time        `json:"lastModified,omitempty"`
+}
+
+// Dealer is the reseller an account belongs to.
+type Dealer struct {
+	ID           string          `json:"id,omitempty"`
+	Name         string          `json:"name,omitempty"`
+	Organization *Organization   `json:"organization,omitempty"`
+	Contact      *AccountContact `json:"contact,omitempty"`
+}
+
+// Organization holds the legal entity behind an account or dealer.
+type Organization struct {
+	Name        string `json:"name,omitempty"`
+	OrgNo       string `json:"orgNo,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+// AccountContact is the contact person for an account or dealer.
+type AccountContact struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"`
+}
+
+// Address is a postal address.
+type Address struct {
+	Street     string `json:"street,omitempty"`
+	PostalCode string `json:"postalCode,omitempty"`
+	City       string `json:"city,omitempty"`
+	Country    string `json:"country,omitempty"`
+}
+
+// Branding holds the default look of the signing and identification pages for an account, as well as the default sender used
+// in notifications.
+type Branding struct {
+	Language   string   `json:"language,omitempty"`
+	LogoURL    string   `json:"logoUrl,omitempty"`
+	Styling    *Styling `json:"styling,omitempty"`
+	SenderName string   `json:"senderName,omitempty"`
+	SmsSender  string   `json:"smsSender,omitempty"`
+}
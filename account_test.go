@@ -0,0 +1,125 @@
+package signicat
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestAccountService_RetrieveAccount(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, "/admin/account", req.URL.Path)
+		if _, err := io.WriteString(res, `{"id":"someAccountId","organization":{"orgNo":"123456785"}}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	account, err := client.Account.RetrieveAccount(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "someAccountId", account.ID)
+	assert.Equal(t, "123456785", account.Organization.OrgNo)
+}
+
+func TestAccountService_UpdateAccount(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPatch, req.Method)
+		assert.Equal(t, "/admin/account", req.URL.Path)
+
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{"name": "New name"}, body)
+
+		if _, err := io.WriteString(res, `{"id":"someAccountId","name":"New name"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	account, err := client.Account.UpdateAccount(context.Background(), &UpdateAccountRequest{Name: "New name"})
+	assert.NoError(t, err)
+	assert.Equal(t, "New name", account.Name)
+}
+
+func TestAccountService_CreateSubAccount(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/admin/accounts", req.URL.Path)
+		if _, err := io.WriteString(res, `{"id":"someSubAccountId","parentAccountId":"someAccountId"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	account, err := client.Account.CreateSubAccount(context.Background(), &CreateAccountRequest{
+		Name:         "Tenant",
+		Organization: &Organization{OrgNo: "123456785", CountryCode: "NO"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "someSubAccountId", account.ID)
+	assert.Equal(t, "someAccountId", account.ParentAccountID)
+}
+
+func TestAccountService_ListSubAccounts(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, "/admin/accounts", req.URL.Path)
+		if _, err := io.WriteString(res, `[{"id":"first"},{"id":"second"}]`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	accounts, err := client.Account.ListSubAccounts(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 2)
+	assert.Equal(t, "second", accounts[1].ID)
+}
+
+func TestAccountService_RetrieveDealer(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, "/admin/dealer", req.URL.Path)
+		if _, err := io.WriteString(res, `{"id":"someDealerId","name":"Dealer"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	dealer, err := client.Account.RetrieveDealer(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "someDealerId", dealer.ID)
+}
+
+func TestAccountService_UpdateBranding(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPut, req.Method)
+		assert.Equal(t, "/admin/accounts/someAccountId/branding", req.URL.Path)
+		if _, err := io.WriteString(res, `{"styling":{"colorTheme":"Blue"}}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	branding, err := client.Account.UpdateBranding(context.Background(), "someAccountId", &Branding{
+		Styling: &Styling{ColorTheme: ColorThemeBlue},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ColorThemeBlue, branding.Styling.ColorTheme)
+}
@@ -0,0 +1,308 @@
+// Package archive copies the signed files of documents to long-term storage, with a metadata sidecar next to each file.
+//
+// Archiving is idempotent. A file whose sidecar already exists is not downloaded or stored again, so a document can be archived
+// again after a failure, or by a job which runs over all documents.
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/larwef/signicat"
+	"github.com/larwef/signicat/internal/sniff"
+)
+
+// DefaultPathTemplate is the path template used when Options.PathTemplate is empty.
+const DefaultPathTemplate = "{{if .ExternalID}}{{.ExternalID}}/{{end}}{{.DocumentID}}/{{.Format}}{{.Ext}}"
+
+// MetadataSuffix is appended to the path of a file to get the path of its metadata sidecar.
+const MetadataSuffix = ".metadata.json"
+
+// Extensions of the archived files, keyed by file format. The unsigned file keeps the extension of the original file name.
+var extensions = map[string]string{
+	signicat.FileFormatNative:            ".xml",
+	signicat.FileFormatStandardPackaging: ".xml",
+	signicat.FileFormatPades:             ".pdf",
+	signicat.FileFormatXades:             ".xml",
+}
+
+// Archiver archives the signed files of a document.
+type Archiver interface {
+	Archive(ctx context.Context, documentID string) (*Result, error)
+}
+
+// Store is where archived files are kept. Keys are slash separated paths.
+type Store interface {
+	// Exists reports whether there is a file at key.
+	Exists(ctx context.Context, key string) (bool, error)
+	// Put stores the size bytes read from r at key, replacing any existing file. A file must never be left partially written.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+}
+
+// Options configures an archiver.
+type Options struct {
+	// Formats are the file formats to archive. Defaults to signicat.FileFormatPades.
+	Formats []string
+	// PathTemplate is a text/template for the path of each file, executed with a PathData. Defaults to DefaultPathTemplate.
+	PathTemplate string
+	// Now returns the time written to the sidecars. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// PathData is the data PathTemplate is executed with.
+type PathData struct {
+	DocumentID string
+	ExternalID string
+	Format     string
+	// Ext is the extension of the file format, including the dot.
+	Ext string
+}
+
+// Result is the outcome of archiving a document.
+type Result struct {
+	DocumentID string
+	Files      []*File
+}
+
+// File is an archived file.
+type File struct {
+	Format      string
+	Key         string
+	MetadataKey string
+	// Skipped is true if the file was archived by an earlier run. Size and SHA256 are only set when the file is stored.
+	Skipped bool
+	Size    int64
+	SHA256  string
+}
+
+// Metadata is the content of a sidecar.
+type Metadata struct {
+	DocumentID  string            `json:"documentId"`
+	ExternalID  string            `json:"externalId,omitempty"`
+	Title       string            `json:"title,omitempty"`
+	Format      string            `json:"format"`
+	ContentType string            `json:"contentType"`
+	Size        int64             `json:"size"`
+	SHA256      string            `json:"sha256"`
+	Archived    time.Time         `json:"archived"`
+	Signers     []*SignerMetadata `json:"signers,omitempty"`
+}
+
+// SignerMetadata is a signer who signed an archived document.
+type SignerMetadata struct {
+	ExternalSignerID string     `json:"externalSignerId,omitempty"`
+	FullName         string     `json:"fullName,omitempty"`
+	SignatureMethod  string     `json:"signatureMethod,omitempty"`
+	SignedTime       *time.Time `json:"signedTime,omitempty"`
+}
+
+// New returns an archiver storing files in store.
+func New(signature *signicat.SignatureService, store Store, opts *Options) (Archiver, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	text := opts.PathTemplate
+	if text == "" {
+		text = DefaultPathTemplate
+	}
+	tmpl, err := template.New("path").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("path template: %v", err)
+	}
+
+	a := &archiver{signature: signature, store: store, path: tmpl, formats: opts.Formats, now: opts.Now}
+	if len(a.formats) == 0 {
+		a.formats = []string{signicat.FileFormatPades}
+	}
+	if a.now == nil {
+		a.now = time.Now
+	}
+
+	return a, nil
+}
+
+// NewFilesystem returns an archiver storing files in the directory dir.
+func NewFilesystem(signature *signicat.SignatureService, dir string, opts *Options) (Archiver, error) {
+	return New(signature, &FileStore{Dir: dir}, opts)
+}
+
+// NewS3 returns an archiver storing files in an S3 compatible bucket.
+func NewS3(signature *signicat.SignatureService, store *S3Store, opts *Options) (Archiver, error) {
+	return New(signature, store, opts)
+}
+
+type archiver struct {
+	signature *signicat.SignatureService
+	store     Store
+	path      *template.Template
+	formats   []string
+	now       func() time.Time
+}
+
+// Archive stores the configured file formats of a signed document. Returns a *signicat.StatusError if the document isn't signed,
+// and an error if a format isn't available for it.
+func (a *archiver) Archive(ctx context.Context, documentID string) (*Result, error) {
+	document, err := a.signature.RetrieveDocument(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	status := document.Status
+	if status == nil {
+		status = &signicat.Status{}
+	}
+	if status.DocumentStatus != signicat.DocumentStatusSigned {
+		return nil, &signicat.StatusError{Operation: "archive", DocumentID: documentID, DocumentStatus: status.DocumentStatus}
+	}
+
+	result := &Result{DocumentID: documentID}
+	for _, format := range a.formats {
+		if !status.CanDownload(format) {
+			return result, fmt.Errorf("file format %s is not available for document %s", format, documentID)
+		}
+
+		file, err := a.archive(ctx, document, format)
+		if err != nil {
+			return result, fmt.Errorf("archiving %s file: %w", format, err)
+		}
+		result.Files = append(result.Files, file)
+	}
+
+	return result, nil
+}
+
+func (a *archiver) archive(ctx context.Context, document *signicat.Document, format string) (*File, error) {
+	key, err := a.key(document, format)
+	if err != nil {
+		return nil, err
+	}
+	file := &File{Format: format, Key: key, MetadataKey: key + MetadataSuffix}
+
+	// The sidecar is stored last, so it tells that the file is complete.
+	exists, err := a.store.Exists(ctx, file.MetadataKey)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		file.Skipped = true
+		return file, nil
+	}
+
+	// Files are downloaded to a temporary file, as the size must be known before storing them.
+	tmp, err := ioutil.TempFile("", "signicat-archive-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	head := &headWriter{n: sniff.Len}
+	if err := a.signature.RetrieveFile(ctx, document.DocumentID, format, false, io.MultiWriter(tmp, hash, head)); err != nil {
+		return nil, err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	file.Size = size
+	file.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	contentType := signicat.DetectContentType(head.Bytes(), path.Base(key))
+
+	if err := a.store.Put(ctx, key, tmp, size, contentType); err != nil {
+		return nil, err
+	}
+
+	metadata, err := json.MarshalIndent(a.metadata(document, file, contentType), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := a.store.Put(ctx, file.MetadataKey, bytes.NewReader(metadata), int64(len(metadata)), "application/json"); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// key returns the path of a file format of document, from the path template.
+func (a *archiver) key(document *signicat.Document, format string) (string, error) {
+	ext, ok := extensions[format]
+	if !ok && document.DataToSign != nil {
+		ext = path.Ext(document.DataToSign.FileName)
+	}
+
+	var buf strings.Builder
+	err := a.path.Execute(&buf, &PathData{
+		DocumentID: document.DocumentID,
+		ExternalID: document.ExternalID,
+		Format:     format,
+		Ext:        ext,
+	})
+	if err != nil {
+		return "", fmt.Errorf("path template: %v", err)
+	}
+
+	key := buf.String()
+	if key == "" || path.IsAbs(key) || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return "", fmt.Errorf("path %q is not a clean relative path", key)
+	}
+
+	return key, nil
+}
+
+func (a *archiver) metadata(document *signicat.Document, file *File, contentType string) *Metadata {
+	metadata := &Metadata{
+		DocumentID:  document.DocumentID,
+		ExternalID:  document.ExternalID,
+		Title:       document.Title,
+		Format:      file.Format,
+		ContentType: contentType,
+		Size:        file.Size,
+		SHA256:      file.SHA256,
+		Archived:    a.now().UTC(),
+	}
+	for _, signer := range document.Signers {
+		if ds := signer.DocumentSignature; ds != nil {
+			metadata.Signers = append(metadata.Signers, &SignerMetadata{
+				ExternalSignerID: signer.ExternalSignerID,
+				FullName:         ds.FullName,
+				SignatureMethod:  ds.SignatureMethod,
+				SignedTime:       ds.SignedTime,
+			})
+		}
+	}
+
+	return metadata
+}
+
+// headWriter keeps the first n bytes written to it.
+type headWriter struct {
+	bytes.Buffer
+	n int
+}
+
+func (h *headWriter) Write(p []byte) (int, error) {
+	if rest := h.n - h.Len(); rest > 0 {
+		if len(p) < rest {
+			rest = len(p)
+		}
+		h.Buffer.Write(p[:rest])
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,181 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/larwef/signicat"
+	"github.com/stretchr/testify/assert"
+)
+
+var archived = time.Date(2020, 6, 27, 8, 0, 0, 0, time.UTC)
+
+// setup serves a signed document, and counts the files retrieved.
+func setup(t *testing.T, document string) (*signicat.SignatureService, *int, func()) {
+	files := map[string]string{
+		signicat.FileFormatPades:             "%PDF-1.7 signed contract",
+		signicat.FileFormatStandardPackaging: `<?xml version="1.0"?><sdo:SDO xmlns:sdo="http://www.signicat.com/sdo"/>`,
+	}
+	retrieved := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/signature/documents/someDocumentId", func(res http.ResponseWriter, req *http.Request) {
+		if _, err := io.WriteString(res, document); err != nil {
+			t.Fatal(err)
+		}
+	})
+	mux.HandleFunc("/signature/documents/someDocumentId/files", func(res http.ResponseWriter, req *http.Request) {
+		retrieved++
+		if _, err := io.WriteString(res, files[req.URL.Query().Get("fileFormat")]); err != nil {
+			t.Fatal(err)
+		}
+	})
+	server := httptest.NewServer(mux)
+
+	client, err := signicat.NewClientWithURL(&http.Client{}, server.URL)
+	assert.NoError(t, err)
+
+	return client.Signature, &retrieved, server.Close
+}
+
+const signedDocument = `{
+  "documentId": "someDocumentId",
+  "externalId": "contract-1",
+  "title": "Contract",
+  "status": {"documentStatus": "signed", "completedPackages": ["pades", "standard_packaging"]},
+  "signers": [{"externalSignerId": "someSigner", "documentSignature": {"signatureMethod": "no_bankid_mobile", "fullName": "Ola Nordmann"}}]
+}`
+
+func TestArchiver_Filesystem(t *testing.T) {
+	signature, retrieved, teardown := setup(t, signedDocument)
+	defer teardown()
+
+	dir, err := ioutil.TempDir("", "archive")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	archiver, err := NewFilesystem(signature, dir, &Options{
+		Formats: []string{signicat.FileFormatPades, signicat.FileFormatStandardPackaging},
+		Now:     func() time.Time { return archived },
+	})
+	assert.NoError(t, err)
+
+	result, err := archiver.Archive(context.Background(), "someDocumentId")
+	assert.NoError(t, err)
+	assert.Len(t, result.Files, 2)
+	assert.Equal(t, "contract-1/someDocumentId/pades.pdf", result.Files[0].Key)
+	assert.Equal(t, "contract-1/someDocumentId/standard_packaging.xml", result.Files[1].Key)
+	assert.False(t, result.Files[0].Skipped)
+	assert.Equal(t, 2, *retrieved)
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "contract-1", "someDocumentId", "pades.pdf"))
+	assert.NoError(t, err)
+	assert.Equal(t, "%PDF-1.7 signed contract", string(b))
+
+	b, err = ioutil.ReadFile(filepath.Join(dir, "contract-1", "someDocumentId", "pades.pdf"+MetadataSuffix))
+	assert.NoError(t, err)
+	var metadata Metadata
+	assert.NoError(t, json.Unmarshal(b, &metadata))
+	assert.Equal(t, Metadata{
+		DocumentID:  "someDocumentId",
+		ExternalID:  "contract-1",
+		Title:       "Contract",
+		Format:      signicat.FileFormatPades,
+		ContentType: signicat.ContentTypePDF,
+		Size:        24,
+		SHA256:      result.Files[0].SHA256,
+		Archived:    archived,
+		Signers: []*SignerMetadata{
+			{ExternalSignerID: "someSigner", FullName: "Ola Nordmann", SignatureMethod: signicat.SignatureMethodNoBankIDMobile},
+		},
+	}, metadata)
+
+	// Archiving again doesn't download or store anything.
+	result, err = archiver.Archive(context.Background(), "someDocumentId")
+	assert.NoError(t, err)
+	assert.True(t, result.Files[0].Skipped)
+	assert.True(t, result.Files[1].Skipped)
+	assert.Equal(t, 2, *retrieved)
+
+	// A file without a sidecar is stored again.
+	assert.NoError(t, os.Remove(filepath.Join(dir, "contract-1", "someDocumentId", "pades.pdf"+MetadataSuffix)))
+	result, err = archiver.Archive(context.Background(), "someDocumentId")
+	assert.NoError(t, err)
+	assert.False(t, result.Files[0].Skipped)
+	assert.Equal(t, 3, *retrieved)
+}
+
+func TestArchiver_PathTemplate(t *testing.T) {
+	signature, _, teardown := setup(t, signedDocument)
+	defer teardown()
+
+	store := &memoryStore{files: make(map[string][]byte)}
+
+	archiver, err := New(signature, store, &Options{PathTemplate: "signed/{{.DocumentID}}{{.Ext}}"})
+	assert.NoError(t, err)
+	_, err = archiver.Archive(context.Background(), "someDocumentId")
+	assert.NoError(t, err)
+	assert.Contains(t, store.files, "signed/someDocumentId.pdf")
+	assert.Contains(t, store.files, "signed/someDocumentId.pdf"+MetadataSuffix)
+
+	for _, text := range []string{"../{{.DocumentID}}", "/{{.DocumentID}}", "{{.ExternalID}}//{{.DocumentID}}"} {
+		archiver, err = New(signature, store, &Options{PathTemplate: text})
+		assert.NoError(t, err)
+		_, err = archiver.Archive(context.Background(), "someDocumentId")
+		assert.Error(t, err, text)
+	}
+
+	_, err = New(signature, store, &Options{PathTemplate: "{{.DocumentID"})
+	assert.Error(t, err)
+}
+
+func TestArchiver_NotSigned(t *testing.T) {
+	signature, _, teardown := setup(t, `{"documentId":"someDocumentId","status":{"documentStatus":"unsigned"}}`)
+	defer teardown()
+
+	archiver, err := New(signature, &memoryStore{files: make(map[string][]byte)}, nil)
+	assert.NoError(t, err)
+
+	_, err = archiver.Archive(context.Background(), "someDocumentId")
+	var statusErr *signicat.StatusError
+	assert.True(t, errors.As(err, &statusErr))
+}
+
+func TestArchiver_FormatNotAvailable(t *testing.T) {
+	signature, _, teardown := setup(t, `{"documentId":"someDocumentId","status":{"documentStatus":"signed","completedPackages":["pades"]}}`)
+	defer teardown()
+
+	archiver, err := New(signature, &memoryStore{files: make(map[string][]byte)}, &Options{Formats: []string{signicat.FileFormatXades}})
+	assert.NoError(t, err)
+
+	_, err = archiver.Archive(context.Background(), "someDocumentId")
+	assert.Error(t, err)
+}
+
+type memoryStore struct {
+	files map[string][]byte
+}
+
+func (s *memoryStore) Exists(ctx context.Context, key string) (bool, error) {
+	_, ok := s.files[key]
+	return ok, nil
+}
+
+func (s *memoryStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.files[key] = b
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package signicat
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// The golden files in testdata/conformance are written from the Signicat Express OpenAPI schema. <Type>.json has every field of
+// the schema set, and <Type>.zero.json is what the zero value must serialize to, ie. only the required fields.
+var conformanceTypes = map[string]func() interface{}{
+	"CreateDocumentRequest": func() interface{} { return new(CreateDocumentRequest) },
+	"SignerRequest":         func() interface{} { return new(SignerRequest) },
+	"RedirectSettings":      func() interface{} { return new(RedirectSettings) },
+	"SignatureType":         func() interface{} { return new(SignatureType) },
+	"Authentication":        func() interface{} { return new(Authentication) },
+	"UI":                    func() interface{} { return new(UI) },
+	"Styling":               func() interface{} { return new(Styling) },
+	"SignerInfo":            func() interface{} { return new(SignerInfo) },
+	"Mobile":                func() interface{} { return new(Mobile) },
+	"OrganizationInfo":      func() interface{} { return new(OrganizationInfo) },
+	"DataToSign":            func() interface{} { return new(DataToSign) },
+	"ContactDetails":        func() interface{} { return new(ContactDetails) },
+	"Notification":          func() interface{} { return new(Notification) },
+	"SignRequest":           func() interface{} { return new(SignRequest) },
+	"Email":                 func() interface{} { return new(Email) },
+	"Sms":                   func() interface{} { return new(Sms) },
+	"Advanced":              func() interface{} { return new(Advanced) },
+	"TimeToLive":            func() interface{} { return new(TimeToLive) },
+	"Reminder":              func() interface{} { return new(Reminder) },
+	"SignatureReceipt":      func() interface{} { return new(SignatureReceipt) },
+	"FinalReceipt":          func() interface{} { return new(FinalReceipt) },
+	"AdditionalRecipient":   func() interface{} { return new(AdditionalRecipient) },
+	"CanceledReceipt":       func() interface{} { return new(CanceledReceipt) },
+	"ExpiredReceipt":        func() interface{} { return new(ExpiredReceipt) },
+	"Notifications":         func() interface{} { return new(Notifications) },
+	"Setup":                 func() interface{} { return new(Setup) },
+	"Document":              func() interface{} { return new(Document) },
+	"SignerResponse":        func() interface{} { return new(SignerResponse) },
+	"DocumentSignature":     func() interface{} { return new(DocumentSignature) },
+	"SocialSecurityNumber":  func() interface{} { return new(SocialSecurityNumber) },
+	"Status":                func() interface{} { return new(Status) },
+}
+
+func TestConformance(t *testing.T) {
+	for name, newValue := range conformanceTypes {
+		t.Run(name, func(t *testing.T) {
+			golden := readGolden(t, name+".json")
+
+			// Every field in the schema must be known, and survive a round trip unchanged.
+			v := newValue()
+			dec := json.NewDecoder(bytes.NewReader(golden))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(v); err != nil {
+				t.Fatalf("decoding golden file: %v", err)
+			}
+			encoded, err := json.Marshal(v)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(golden), string(encoded))
+
+			// Only the required fields may be sent when empty.
+			encoded, err = json.Marshal(newValue())
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(readGolden(t, name+".zero.json")), string(encoded))
+		})
+	}
+}
+
+func readGolden(t *testing.T, name string) []byte {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "conformance", name))
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+	}
+
+	return b
+}
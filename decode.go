@@ -0,0 +1,256 @@
+package signicat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Available decode modes.
+const (
+	// DecodeLenient ignores fields in responses which are not modelled. On the types with an Extra field, the unknown fields are
+	// kept there. This is the default.
+	DecodeLenient DecodeMode = iota
+	// DecodeStrict fails on any field in a response which is not modelled. Useful in contract tests, to find out when the API
+	// changes.
+	DecodeStrict
+)
+
+// DecodeMode decides what to do with fields in responses which are not modelled.
+type DecodeMode int
+
+// UnknownFieldsError is returned when decoding strictly and the response has fields which are not modelled.
+type UnknownFieldsError struct {
+	// Fields holds the path of each unknown field, eg. signers[0].someField.
+	Fields []string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("json: unknown fields %s", strings.Join(e.Fields, ", "))
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	rawMessageType      = reflect.TypeOf(json.RawMessage{})
+)
+
+func (c *Client) decode(r io.Reader, v interface{}) error {
+	if c.decodeMode != DecodeStrict {
+		// Ignore EOF errors caused by empty response body.
+		if err := json.NewDecoder(r).Decode(v); err != nil && err != io.EOF {
+			return err
+		}
+		return nil
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	if fields := unknownFields(data, reflect.TypeOf(v), ""); len(fields) > 0 {
+		return &UnknownFieldsError{Fields: fields}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON decodes a document, keeping fields which are not modelled in Extra.
+func (d *Document) UnmarshalJSON(data []byte) error {
+	type document Document
+	return unmarshalWithExtra(data, (*document)(d), &d.Extra)
+}
+
+// MarshalJSON encodes a document, including the fields in Extra.
+func (d Document) MarshalJSON() ([]byte, error) {
+	type document Document
+	return marshalWithExtra(document(d), d.Extra)
+}
+
+// UnmarshalJSON decodes a signer, keeping fields which are not modelled in Extra.
+func (s *SignerResponse) UnmarshalJSON(data []byte) error {
+	type signerResponse SignerResponse
+	return unmarshalWithExtra(data, (*signerResponse)(s), &s.Extra)
+}
+
+// MarshalJSON encodes a signer, including the fields in Extra.
+func (s SignerResponse) MarshalJSON() ([]byte, error) {
+	type signerResponse SignerResponse
+	return marshalWithExtra(signerResponse(s), s.Extra)
+}
+
+// UnmarshalJSON decodes a status, keeping fields which are not modelled in Extra.
+func (s *Status) UnmarshalJSON(data []byte) error {
+	type status Status
+	return unmarshalWithExtra(data, (*status)(s), &s.Extra)
+}
+
+// MarshalJSON encodes a status, including the fields in Extra.
+func (s Status) MarshalJSON() ([]byte, error) {
+	type status Status
+	return marshalWithExtra(status(s), s.Extra)
+}
+
+// unmarshalWithExtra decodes data into v, which must be a pointer to a struct, and the fields v doesn't have into extra.
+func unmarshalWithExtra(data []byte, v interface{}, extra *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	t := reflect.TypeOf(v).Elem()
+	for name := range fields {
+		if _, ok := fieldByJSONName(t, name); ok {
+			delete(fields, name)
+		}
+	}
+
+	*extra = nil
+	if len(fields) > 0 {
+		*extra = fields
+	}
+
+	return nil
+}
+
+// marshalWithExtra encodes v, adding the fields in extra which v doesn't have.
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// unknownFields returns the path of every field in data which has no corresponding field in t.
+func unknownFields(data []byte, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		// Types decoding themselves are leaves, unless they only do so to keep track of unknown fields.
+		if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+			if _, ok := t.FieldByName("Extra"); !ok {
+				return nil
+			}
+		}
+
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil
+		}
+
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var unknown []string
+		for _, name := range names {
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+
+			f, ok := fieldByJSONName(t, name)
+			if !ok {
+				unknown = append(unknown, fieldPath)
+				continue
+			}
+			unknown = append(unknown, unknownFields(fields[name], f.Type, fieldPath)...)
+		}
+
+		return unknown
+	case reflect.Slice:
+		if t == rawMessageType {
+			return nil
+		}
+
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return nil
+		}
+
+		var unknown []string
+		for i, elem := range elems {
+			unknown = append(unknown, unknownFields(elem, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+
+		return unknown
+	case reflect.Map:
+		var elems map[string]json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return nil
+		}
+
+		keys := make([]string, 0, len(elems))
+		for key := range elems {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var unknown []string
+		for _, key := range keys {
+			unknown = append(unknown, unknownFields(elems[key], t.Elem(), fmt.Sprintf("%s[%s]", path, key))...)
+		}
+
+		return unknown
+	}
+
+	return nil
+}
+
+// fieldByJSONName returns the field of the struct type t which the JSON field name decodes into. Like encoding/json, the match is
+// case insensitive.
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+
+		if strings.EqualFold(tag, name) {
+			return f, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,86 @@
+package signicat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const documentWithUnknownFields = `{
+	"documentId": "someDocumentId",
+	"lastUpdated": "2020-06-26T10:00:00Z",
+	"signers": [{"id": "someSignerId", "tags": ["a"]}],
+	"status": {"documentStatus": "signed", "attachmentPackages": {}},
+	"contactDetails": {"email": "support@example.com", "fax": "12345678"}
+}`
+
+func TestClient_Do_Lenient(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		if _, err := io.WriteString(res, documentWithUnknownFields); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	document, err := client.Signature.RetrieveDocument(context.Background(), "someDocumentId")
+	assert.NoError(t, err)
+	assert.Equal(t, "someDocumentId", document.DocumentID)
+	assert.Equal(t, map[string]json.RawMessage{"lastUpdated": json.RawMessage(`"2020-06-26T10:00:00Z"`)}, document.Extra)
+	assert.Equal(t, map[string]json.RawMessage{"tags": json.RawMessage(`["a"]`)}, document.Signers[0].Extra)
+	assert.Equal(t, map[string]json.RawMessage{"attachmentPackages": json.RawMessage(`{}`)}, document.Status.Extra)
+	assert.Equal(t, DocumentStatusSigned, document.Status.DocumentStatus)
+
+	// The unknown fields survive a round trip.
+	encoded, err := json.Marshal(document)
+	assert.NoError(t, err)
+	var decoded Document
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, document.Extra, decoded.Extra)
+	assert.Equal(t, document.Signers[0].Extra, decoded.Signers[0].Extra)
+}
+
+func TestClient_Do_Strict(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := NewClientWithURL(&http.Client{}, server.URL, WithDecodeMode(DecodeStrict))
+	if err != nil {
+		panic(fmt.Sprintf("couldnt set up test client: %v", err))
+	}
+
+	mux.HandleFunc("/signature/documents/unknown", func(res http.ResponseWriter, req *http.Request) {
+		if _, err := io.WriteString(res, documentWithUnknownFields); err != nil {
+			t.Fatal(err)
+		}
+	})
+	mux.HandleFunc("/signature/documents/known", func(res http.ResponseWriter, req *http.Request) {
+		if _, err := io.WriteString(res, `{"documentId":"known","status":{"documentStatus":"unsigned"}}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+	mux.HandleFunc("/signature/documents/known/status", func(res http.ResponseWriter, req *http.Request) {})
+
+	_, err = client.Signature.RetrieveDocument(context.Background(), "unknown")
+	assert.Equal(t, &UnknownFieldsError{Fields: []string{
+		"contactDetails.fax",
+		"lastUpdated",
+		"signers[0].tags",
+		"status.attachmentPackages",
+	}}, err)
+
+	document, err := client.Signature.RetrieveDocument(context.Background(), "known")
+	assert.NoError(t, err)
+	assert.Equal(t, "known", document.DocumentID)
+
+	// Empty responses are not an error.
+	_, err = client.Signature.RetrieveDocumentStatus(context.Background(), "known")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,102 @@
+package signicat
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"hash"
+	"io"
+	"strings"
+)
+
+// DigestAlgorithmSHA256 is the algorithm of a ContentDigest.
+const DigestAlgorithmSHA256 = "sha256"
+
+// ContentDigest is the digest of the file to sign, computed by the client when a document is created. It is kept with the
+// document to later prove that the signed files are of the same file. See evidence.VerifyContent.
+type ContentDigest struct {
+	// Algorithm is the hash function used. Always DigestAlgorithmSHA256.
+	Algorithm string `json:"algorithm"`
+	Value     []byte `json:"value"`
+	// Size is the size of the file in bytes.
+	Size int64 `json:"size"`
+}
+
+// Matches reports whether the digest is the digest of content.
+func (d *ContentDigest) Matches(content []byte) bool {
+	if d.Algorithm != DigestAlgorithmSHA256 || int64(len(content)) != d.Size {
+		return false
+	}
+	sum := sha256.Sum256(content)
+
+	return bytes.Equal(sum[:], d.Value)
+}
+
+// NewContentDigest returns the digest of the file in dataToSign.Base64Content. It returns nil if the file is given by
+// DataToSign.FileID or DataToSign.Content, as those can't be read again. The file is decoded while it is hashed, so it isn't
+// copied in memory.
+func NewContentDigest(dataToSign *DataToSign) (*ContentDigest, error) {
+	if dataToSign == nil || dataToSign.Content != nil || dataToSign.Base64Content == "" {
+		return nil, nil
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, base64.NewDecoder(base64.StdEncoding, strings.NewReader(dataToSign.Base64Content)))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ContentDigest{Algorithm: DigestAlgorithmSHA256, Value: hash.Sum(nil), Size: size}, nil
+}
+
+// digestReader computes the digest of a streamed file as it is sent.
+type digestReader struct {
+	r    io.Reader
+	hash hash.Hash
+	size int64
+	eof  bool
+}
+
+func newDigestReader(r io.Reader) *digestReader {
+	return &digestReader{r: r, hash: sha256.New()}
+}
+
+func (d *digestReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	d.hash.Write(p[:n])
+	d.size += int64(n)
+	d.eof = d.eof || err == io.EOF
+
+	return n, err
+}
+
+// digest returns the digest of the file, or nil if it wasn't read to the end. It must not be called while the file is read.
+func (d *digestReader) digest() *ContentDigest {
+	if !d.eof {
+		return nil
+	}
+
+	return &ContentDigest{Algorithm: DigestAlgorithmSHA256, Value: d.hash.Sum(nil), Size: d.size}
+}
+
+// withContentDigest returns createReq prepared to compute the digest of its file as it is sent, and a function returning the
+// digest once the request has been sent and the streaming has finished, see finishStreaming. The digest is nil if the file
+// wasn't sent in full.
+func withContentDigest(createReq *CreateDocumentRequest) (*CreateDocumentRequest, func() *ContentDigest) {
+	if streamedContent(createReq) == nil {
+		digest, err := NewContentDigest(createReq.DataToSign)
+		if err != nil {
+			// Signicat rejects the request, so there is no document for the digest.
+			digest = nil
+		}
+		return createReq, func() *ContentDigest { return digest }
+	}
+
+	r := *createReq
+	dataToSign := *r.DataToSign
+	reader := newDigestReader(dataToSign.Content)
+	dataToSign.Content = reader
+	r.DataToSign = &dataToSign
+
+	return &r, reader.digest
+}
@@ -0,0 +1,91 @@
+package signicat
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestSignatureService_CreateDocument_ContentDigest(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		_, err := io.Copy(ioutil.Discard, req.Body)
+		assert.NoError(t, err)
+		if _, err := io.WriteString(res, `{"documentId":"someDocumentId"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	content := []byte("%PDF-1.4\n%contract")
+	sum := sha256.Sum256(content)
+	want := &ContentDigest{Algorithm: DigestAlgorithmSHA256, Value: sum[:], Size: int64(len(content))}
+
+	for name, dataToSign := range map[string]*DataToSign{
+		"base64":   {FileName: "contract.pdf", Base64Content: base64.StdEncoding.EncodeToString(content)},
+		"streamed": {FileName: "contract.pdf", Content: bytes.NewReader(content)},
+	} {
+		document, err := client.Signature.CreateDocument(context.Background(), &CreateDocumentRequest{DataToSign: dataToSign})
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, document.ContentDigest, name)
+		assert.True(t, document.ContentDigest.Matches(content), name)
+	}
+
+	// The file isn't available to the client.
+	document, err := client.Signature.CreateDocument(context.Background(), &CreateDocumentRequest{
+		DataToSign: &DataToSign{FileName: "contract.pdf", FileID: "someFileId"},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, document.ContentDigest)
+}
+
+func TestContentDigest(t *testing.T) {
+	content := []byte("%PDF-1.4\n%contract")
+	digest, err := NewContentDigest(&DataToSign{Base64Content: base64.StdEncoding.EncodeToString(content)})
+	assert.NoError(t, err)
+	assert.True(t, digest.Matches(content))
+	assert.False(t, digest.Matches([]byte("%PDF-1.4\n%another")))
+
+	// The digest is saved with the document.
+	b, err := json.Marshal(&Document{DocumentID: "someDocumentId", ContentDigest: digest})
+	assert.NoError(t, err)
+	var document Document
+	assert.NoError(t, json.Unmarshal(b, &document))
+	assert.Equal(t, digest, document.ContentDigest)
+	assert.Empty(t, document.Extra)
+
+	_, err = NewContentDigest(&DataToSign{Base64Content: "not base64"})
+	assert.Error(t, err)
+}
+
+func TestSignatureService_CreateDocument_ContentDigest_Unread(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	// The server responds before it has read the file, so only a part of it is sent. Run with -race to check that the file
+	// isn't read after the response is handled.
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		if _, err := io.WriteString(res, `{"documentId":"someDocumentId"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	content := bytes.NewReader(make([]byte, 8<<20))
+	document, err := client.Signature.CreateDocument(context.Background(), &CreateDocumentRequest{
+		DataToSign: &DataToSign{FileName: "contract.pdf", Content: content},
+	})
+	if err != nil {
+		// The client may also fail to send the request.
+		return
+	}
+	assert.Equal(t, "someDocumentId", document.DocumentID)
+	assert.Nil(t, document.ContentDigest)
+}
@@ -0,0 +1,150 @@
+// Package embed helps embedding the signing page in an iframe, when using one of the iframe redirect modes.
+//
+// Iframe renders the markup for a signer, including a script which relays the web messaging events from the signing page to a
+// server side handler. Handler receives the relayed events, validates them and passes them on.
+package embed
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/larwef/signicat"
+)
+
+var iframeTemplate = template.Must(template.New("iframe").Parse(`<iframe src="{{.Src}}" title="{{.Title}}" width="{{.Width}}" height="{{.Height}}" style="border: none;" allow="camera; microphone"></iframe>
+{{- if .RelayURL}}
+<script{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>
+window.addEventListener("message", function (event) {
+	if (event.origin !== {{.Origin}}) {
+		return;
+	}
+	var body = typeof event.data === "string" ? event.data : JSON.stringify(event.data);
+	fetch({{.RelayURL}}, {method: "POST", credentials: "same-origin", headers: {"Content-Type": "application/json"}, body: body});
+});
+</script>
+{{- end}}
+`))
+
+// IframeData is the data used to render the iframe. It can also be used with your own templates.
+type IframeData struct {
+	// Src is the URL of the signing page.
+	Src string
+	// Origin is the origin of the signing page. Web messages from other origins must be ignored.
+	Origin string
+	Title  string
+	Width  string
+	Height string
+	// RelayURL is where web messaging events are posted. No script is rendered if empty.
+	RelayURL string
+	// Nonce is set on the script tag, for use with a Content-Security-Policy.
+	Nonce string
+}
+
+// Options are the optional settings when rendering the iframe.
+type Options struct {
+	Title    string
+	Width    string
+	Height   string
+	RelayURL string
+	Nonce    string
+}
+
+// NewIframeData returns the data needed to embed the signing page of signer. The URL of the signer must be absolute and use https.
+func NewIframeData(signer *signicat.SignerResponse, opts *Options) (*IframeData, error) {
+	if signer == nil || signer.URL == "" {
+		return nil, fmt.Errorf("signer has no url")
+	}
+
+	u, err := url.Parse(signer.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signer url: %v", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("signer url must be an absolute https url: %s", signer.URL)
+	}
+
+	data := &IframeData{
+		Src:    u.String(),
+		Origin: u.Scheme + "://" + u.Host,
+		Title:  "Sign document",
+		Width:  "100%",
+		Height: "600",
+	}
+
+	if opts != nil {
+		if opts.Title != "" {
+			data.Title = opts.Title
+		}
+		if opts.Width != "" {
+			data.Width = opts.Width
+		}
+		if opts.Height != "" {
+			data.Height = opts.Height
+		}
+		data.RelayURL = opts.RelayURL
+		data.Nonce = opts.Nonce
+	}
+
+	return data, nil
+}
+
+// Iframe returns the markup embedding the signing page of signer. If opts has a RelayURL, a script relaying the web messaging
+// events to it is included, see Handler.
+func Iframe(signer *signicat.SignerResponse, opts *Options) (template.HTML, error) {
+	data, err := NewIframeData(signer, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := iframeTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return template.HTML(buf.String()), nil
+}
+
+// ValidateDomain checks that settings can be used to embed the signing page on pageURL, the page hosting the iframe. The redirect
+// mode must be one of the iframe modes, and the domain must be the host of pageURL.
+func ValidateDomain(settings *signicat.RedirectSettings, pageURL string) error {
+	if settings == nil {
+		return fmt.Errorf("no redirect settings")
+	}
+
+	switch settings.RedirectMode {
+	case signicat.RedirectModeIframeWithWebMessaging,
+		signicat.RedirectModeIframeWithRedirect,
+		signicat.RedirectModeIframeWithRedirectAndWebMessaging:
+	default:
+		return fmt.Errorf("redirect mode %q can't be embedded in an iframe", settings.RedirectMode)
+	}
+
+	if settings.Domain == "" {
+		return fmt.Errorf("domain must be set when embedding in an iframe")
+	}
+	if strings.ContainsAny(settings.Domain, "/:") {
+		return fmt.Errorf("domain must be a host name without scheme, port or path: %s", settings.Domain)
+	}
+
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return fmt.Errorf("invalid page url: %v", err)
+	}
+	host := u.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "" {
+		return fmt.Errorf("page url must be absolute: %s", pageURL)
+	}
+
+	if !strings.EqualFold(host, settings.Domain) {
+		return fmt.Errorf("domain %s doesn't match the host of the page %s", settings.Domain, host)
+	}
+
+	return nil
+}
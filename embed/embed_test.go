@@ -0,0 +1,115 @@
+package embed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/larwef/signicat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIframe(t *testing.T) {
+	signer := &signicat.SignerResponse{URL: "https://sign.idfy.io/?jwt=a&b=\"c\""}
+
+	html, err := Iframe(signer, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, string(html), `<iframe src="https://sign.idfy.io/?jwt=a&amp;b=%22c%22"`)
+	assert.NotContains(t, string(html), "<script")
+
+	html, err = Iframe(signer, &Options{RelayURL: "/signicat/events", Nonce: "abc", Height: "800"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(html), `height="800"`)
+	assert.Contains(t, string(html), `<script nonce="abc">`)
+	assert.Contains(t, string(html), `event.origin !== "https://sign.idfy.io"`)
+	assert.Contains(t, string(html), `fetch("/signicat/events"`)
+
+	_, err = Iframe(&signicat.SignerResponse{URL: "http://sign.idfy.io"}, nil)
+	assert.Error(t, err)
+	_, err = Iframe(&signicat.SignerResponse{}, nil)
+	assert.Error(t, err)
+}
+
+func TestValidateDomain(t *testing.T) {
+	settings := &signicat.RedirectSettings{RedirectMode: signicat.RedirectModeIframeWithWebMessaging, Domain: "example.com"}
+
+	assert.NoError(t, ValidateDomain(settings, "https://example.com/contracts/sign"))
+	assert.NoError(t, ValidateDomain(settings, "https://EXAMPLE.com:8443/"))
+	assert.Error(t, ValidateDomain(settings, "https://www.example.com/"))
+	assert.Error(t, ValidateDomain(settings, "/relative"))
+	assert.Error(t, ValidateDomain(nil, "https://example.com/"))
+	assert.Error(t, ValidateDomain(&signicat.RedirectSettings{RedirectMode: signicat.RedirectModeRedirect, Domain: "example.com"}, "https://example.com/"))
+	assert.Error(t, ValidateDomain(&signicat.RedirectSettings{RedirectMode: signicat.RedirectModeIframeWithRedirect}, "https://example.com/"))
+	assert.Error(t, ValidateDomain(&signicat.RedirectSettings{RedirectMode: signicat.RedirectModeIframeWithRedirect, Domain: "https://example.com"}, "https://example.com/"))
+}
+
+func TestParseEvent(t *testing.T) {
+	event, err := ParseEvent([]byte(`{"type":"sign_success","payload":{"documentId":"someDocumentId","signerId":"someSignerId"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, EventSignSuccess, event.Type)
+	assert.Equal(t, "someDocumentId", event.Payload.DocumentID)
+
+	invalid := []string{
+		`not json`,
+		`{"type":"sign_something","payload":{"documentId":"a","signerId":"b"}}`,
+		`{"type":"sign_error"}`,
+		`{"type":"sign_error","payload":{"signerId":"b"}}`,
+		`{"type":"sign_rejected","payload":{"documentId":"a"}}`,
+	}
+	for _, data := range invalid {
+		_, err := ParseEvent([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var received []*Event
+	handler := &Handler{
+		Origin: "https://example.com",
+		OnEvent: func(ctx context.Context, event *Event) error {
+			received = append(received, event)
+			if event.Type == EventSignError {
+				return errors.New("failed")
+			}
+			return nil
+		},
+	}
+
+	tests := []struct {
+		method, origin, contentType, body string
+		expected                          int
+	}{
+		{http.MethodPost, "https://example.com", "application/json", `{"type":"sign_success","payload":{"documentId":"a","signerId":"b"}}`, http.StatusNoContent},
+		{http.MethodPost, "https://example.com", "application/json", `{"type":"sign_error","payload":{"documentId":"a","signerId":"b"}}`, http.StatusInternalServerError},
+		{http.MethodGet, "https://example.com", "application/json", ``, http.StatusMethodNotAllowed},
+		{http.MethodPost, "https://evil.com", "application/json", `{"type":"sign_success","payload":{"documentId":"a","signerId":"b"}}`, http.StatusForbidden},
+		{http.MethodPost, "", "application/json", `{"type":"sign_success","payload":{"documentId":"a","signerId":"b"}}`, http.StatusForbidden},
+		{http.MethodPost, "https://example.com", "text/plain", `{"type":"sign_success","payload":{"documentId":"a","signerId":"b"}}`, http.StatusUnsupportedMediaType},
+		{http.MethodPost, "https://example.com", "application/json", `{"type":"sign_success"}`, http.StatusBadRequest},
+		{http.MethodPost, "https://example.com", "application/json", strings.Repeat(" ", maxEventSize+1), http.StatusRequestEntityTooLarge},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, "/signicat/events", strings.NewReader(test.body))
+		req.Header.Set("Origin", test.origin)
+		req.Header.Set("Content-Type", test.contentType)
+		res := httptest.NewRecorder()
+
+		handler.ServeHTTP(res, req)
+		assert.Equal(t, test.expected, res.Code, test.body)
+	}
+
+	assert.Len(t, received, 2)
+
+	// Without an origin to check against, no event is accepted.
+	handler.Origin = ""
+	req := httptest.NewRequest(http.MethodPost, "/signicat/events", strings.NewReader(`{"type":"sign_success","payload":{"documentId":"a","signerId":"b"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.Len(t, received, 2)
+}
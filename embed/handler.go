@@ -0,0 +1,119 @@
+package embed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+)
+
+// Available web messaging event types.
+const (
+	EventSignSuccess  = "sign_success"
+	EventSignRejected = "sign_rejected"
+	EventSignError    = "sign_error"
+)
+
+// Max size of a relayed event. The events from the signing page are small, so anything bigger is rejected.
+const maxEventSize = 16 << 10
+
+// Event is a web messaging event sent by the signing page.
+type Event struct {
+	Type    string        `json:"type"`
+	Payload *EventPayload `json:"payload"`
+}
+
+// EventPayload identifies the document and signer the event is about. Error details are only set for sign_error.
+type EventPayload struct {
+	DocumentID       string `json:"documentId"`
+	SignerID         string `json:"signerId,omitempty"`
+	ExternalSignerID string `json:"externalSignerId,omitempty"`
+	ErrorCode        string `json:"errorCode,omitempty"`
+	ErrorMessage     string `json:"errorMessage,omitempty"`
+}
+
+// ParseEvent parses and validates a web messaging event.
+func ParseEvent(data []byte) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("invalid event: %v", err)
+	}
+
+	switch event.Type {
+	case EventSignSuccess, EventSignRejected, EventSignError:
+	default:
+		return nil, fmt.Errorf("unknown event type %q", event.Type)
+	}
+
+	if event.Payload == nil || event.Payload.DocumentID == "" {
+		return nil, fmt.Errorf("event %s has no document id", event.Type)
+	}
+	if event.Payload.SignerID == "" && event.Payload.ExternalSignerID == "" {
+		return nil, fmt.Errorf("event %s has no signer id", event.Type)
+	}
+
+	return &event, nil
+}
+
+// Handler receives the web messaging events relayed by the script rendered by Iframe. Events are parsed and validated before they
+// are passed to OnEvent.
+//
+// The events come from the browser and can be forged by the end user. Use them to update the page, and confirm the status with
+// SignatureService.RetrieveDocument before acting on them.
+type Handler struct {
+	// Origin is the origin of the page hosting the iframe, eg. https://example.com. Requests with another Origin header are
+	// rejected. Required, every request is rejected with 500 if it is empty.
+	Origin string
+	// OnEvent is called for every valid event. Returning an error responds with 500.
+	OnEvent func(ctx context.Context, event *Event) error
+}
+
+func (h *Handler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		res.Header().Set("Allow", http.MethodPost)
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.Origin == "" {
+		http.Error(res, "no origin configured", http.StatusInternalServerError)
+		return
+	}
+	if req.Header.Get("Origin") != h.Origin {
+		http.Error(res, "origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		http.Error(res, "content type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(req.Body, maxEventSize+1))
+	if err != nil {
+		http.Error(res, "unable to read body", http.StatusBadRequest)
+		return
+	}
+	if len(data) > maxEventSize {
+		http.Error(res, "event too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	event, err := ParseEvent(data)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if h.OnEvent != nil {
+		if err := h.OnEvent(req.Context(), event); err != nil {
+			http.Error(res, "unable to handle event", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	res.WriteHeader(http.StatusNoContent)
+}
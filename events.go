@@ -0,0 +1,34 @@
+package signicat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DocumentEvent is an entry in the audit trail of a document, eg. a signer opening or signing it.
+type DocumentEvent struct {
+	ID   string `json:"id,omitempty"`
+	Type string `json:"type,omitempty"`
+	// SignerID is set for events caused by a signer.
+	SignerID    string     `json:"signerId,omitempty"`
+	Description string     `json:"description,omitempty"`
+	IPAddress   string     `json:"ipAddress,omitempty"`
+	Created     *time.Time `json:"created,omitempty"`
+}
+
+// ListDocumentEvents lists the audit trail of a document, oldest first.
+func (s *SignatureService) ListDocumentEvents(ctx context.Context, documentID string) ([]*DocumentEvent, error) {
+	req, err := s.client.NewRequest(http.MethodGet, fmt.Sprintf("/signature/documents/%s/events", documentID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []*DocumentEvent
+	if err := s.client.Do(ctx, req, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
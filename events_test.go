@@ -0,0 +1,29 @@
+package signicat
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestSignatureService_ListDocumentEvents(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, "/signature/documents/someDocumentId/events", req.URL.Path)
+		if _, err := io.WriteString(res, `[{"id":"1","type":"created","created":"2020-06-26T12:00:00Z"},{"id":"2","type":"signed","signerId":"someSignerId"}]`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	events, err := client.Signature.ListDocumentEvents(context.Background(), "someDocumentId")
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "created", events[0].Type)
+	assert.NotNil(t, events[0].Created)
+	assert.Equal(t, "someSignerId", events[1].SignerID)
+}
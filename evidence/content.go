@@ -0,0 +1,125 @@
+package evidence
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/larwef/signicat"
+	"github.com/larwef/signicat/internal/xmldsig"
+	"github.com/larwef/signicat/native"
+	"github.com/larwef/signicat/sdo"
+)
+
+// ErrContentMismatch is returned, wrapped in a *ContentMismatchError, when a signed file isn't of the file the document was
+// created with.
+var ErrContentMismatch = errors.New("signed file doesn't match the original content")
+
+// ErrNotVerifiable is returned, wrapped, when a signed file can't be checked against the original content. It doesn't mean that
+// the file doesn't match.
+var ErrNotVerifiable = errors.New("signed file can't be checked against the original content")
+
+// documentDigestMethods are the signature methods whose native signatures are over the document itself. The others sign data of
+// their own, eg. Swedish BankID signs the text shown to the signer, and NemID an XML document which refers to the document.
+var documentDigestMethods = map[string]bool{
+	signicat.SignatureMethodNoBankIDNetCentric: true,
+	signicat.SignatureMethodNoBankIDMobile:     true,
+	signicat.SignatureMethodNoBuypass:          true,
+}
+
+// ContentMismatchError tells why a signed file doesn't match the original content.
+type ContentMismatchError struct {
+	Format string
+	Reason string
+}
+
+func (e *ContentMismatchError) Error() string {
+	return fmt.Sprintf("%s: %v: %s", e.Format, ErrContentMismatch, e.Reason)
+}
+
+// Unwrap returns ErrContentMismatch.
+func (e *ContentMismatchError) Unwrap() error {
+	return ErrContentMismatch
+}
+
+// VerifyContent checks that a signed file of document is of the file the document was created with, as given by
+// document.ContentDigest. How depends on the format:
+//
+//	pades               the original file must be the start of the signed file
+//	standard_packaging  the document digest of every signature must be the content digest
+//	native              the digest of every signature must be the content digest, read with native.DefaultRegistry
+//
+// The pades check only holds when the signatures are added to the original PDF as incremental updates. A file which was converted
+// to PDF, or isn't a PDF according to DataToSign.FileName, can't be checked. Neither can a PDF which Signicat rewrote while
+// signing, which gives a false mismatch. The native check is only done for Norwegian BankID and Buypass, as the other signature
+// methods don't sign the document itself.
+//
+// Returns a *ContentMismatchError if the file doesn't match, and an error wrapping ErrNotVerifiable if the file can't be checked,
+// eg. because of the signature method, or signatures using another digest algorithm than the content digest.
+func VerifyContent(document *signicat.Document, format string, data []byte) error {
+	digest := document.ContentDigest
+	if digest == nil {
+		return errors.New("document has no content digest")
+	}
+	if digest.Algorithm != signicat.DigestAlgorithmSHA256 {
+		return fmt.Errorf("unsupported content digest algorithm %s", digest.Algorithm)
+	}
+
+	switch format {
+	case signicat.FileFormatPades:
+		if dataToSign := document.DataToSign; dataToSign != nil {
+			if dataToSign.ConvertToPDF {
+				return fmt.Errorf("%w: the original file was converted to PDF", ErrNotVerifiable)
+			}
+			if ext := path.Ext(dataToSign.FileName); !strings.EqualFold(ext, ".pdf") {
+				return fmt.Errorf("%w: the original file %s is not a PDF", ErrNotVerifiable, dataToSign.FileName)
+			}
+		}
+		if int64(len(data)) < digest.Size || !digest.Matches(data[:digest.Size]) {
+			return &ContentMismatchError{Format: format, Reason: "the original file is not the start of the signed file"}
+		}
+		return nil
+
+	case signicat.FileFormatStandardPackaging:
+		parsed, err := sdo.Parse(data)
+		if err != nil {
+			return err
+		}
+		for _, s := range parsed.Signatures {
+			if s.DocumentDigest == nil {
+				return &ContentMismatchError{Format: format, Reason: fmt.Sprintf("signature %s has no document digest", s.ID)}
+			}
+			if h, ok := xmldsig.DigestMethod(s.DocumentDigest.Algorithm); !ok || h != crypto.SHA256 {
+				return fmt.Errorf("%w: signature %s uses digest method %s", ErrNotVerifiable, s.ID, s.DocumentDigest.Algorithm)
+			}
+			if !bytes.Equal(s.DocumentDigest.Value, digest.Value) {
+				return &ContentMismatchError{Format: format, Reason: fmt.Sprintf("signature %s is of another file", s.ID)}
+			}
+		}
+		return nil
+
+	case signicat.FileFormatNative:
+		method := signatureMethod(document)
+		if !documentDigestMethods[method] {
+			return fmt.Errorf("%w: native %s signatures are not over the document", ErrNotVerifiable, method)
+		}
+		signatures, err := native.Decode(method, data)
+		if err != nil {
+			return err
+		}
+		for _, s := range signatures {
+			if s.DigestAlgorithm != crypto.SHA256 {
+				return fmt.Errorf("%w: signature of %s uses digest algorithm %v", ErrNotVerifiable, s.Name, s.DigestAlgorithm)
+			}
+			if !bytes.Equal(s.Digest, digest.Value) {
+				return &ContentMismatchError{Format: format, Reason: fmt.Sprintf("signature of %s is of another file", s.Name)}
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s files aren't supported", ErrNotVerifiable, format)
+}
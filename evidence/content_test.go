@@ -0,0 +1,118 @@
+package evidence
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/larwef/signicat"
+	"github.com/larwef/signicat/internal/testpki"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyContent(t *testing.T) {
+	contract := []byte("%PDF-1.7 contract")
+	another := []byte("%PDF-1.7 another contract")
+	sum := sha256.Sum256(contract)
+	document := &signicat.Document{
+		ContentDigest: &signicat.ContentDigest{Algorithm: signicat.DigestAlgorithmSHA256, Value: sum[:], Size: int64(len(contract))},
+		Signers: []*signicat.SignerResponse{
+			{DocumentSignature: &signicat.DocumentSignature{SignatureMethod: signicat.SignatureMethodNoBankIDMobile}},
+		},
+	}
+	ola, ca := testpki.Chain("Ola Nordmann", "9578-6000-4-123456")
+	signed := time.Date(2020, 6, 26, 12, 0, 0, 0, time.UTC)
+
+	sdoOf := func(document []byte) []byte {
+		return []byte(`<sdo:SDO xmlns:sdo="http://www.signicat.com/sdo">` + testpki.Signature(&testpki.SignatureOptions{
+			ID: "ola", Document: document, Certificates: []*x509.Certificate{ola, ca},
+		}) + `</sdo:SDO>`)
+	}
+	nativeOf := func(document []byte) []byte {
+		return []byte(`<SEIDSDO><CMSSignature>` +
+			base64.StdEncoding.EncodeToString(testpki.SignedData(document, signed, ola, ca)) + `</CMSSignature></SEIDSDO>`)
+	}
+
+	tests := []struct {
+		format   string
+		data     []byte
+		mismatch bool
+	}{
+		{format: signicat.FileFormatPades, data: append(append([]byte{}, contract...), "\n% incremental update"...)},
+		{format: signicat.FileFormatPades, data: append(append([]byte{}, another...), "\n% incremental update"...), mismatch: true},
+		{format: signicat.FileFormatPades, data: contract[:4], mismatch: true},
+		{format: signicat.FileFormatStandardPackaging, data: sdoOf(contract)},
+		{format: signicat.FileFormatStandardPackaging, data: sdoOf(another), mismatch: true},
+		{format: signicat.FileFormatNative, data: nativeOf(contract)},
+		{format: signicat.FileFormatNative, data: nativeOf(another), mismatch: true},
+	}
+
+	for i, test := range tests {
+		err := VerifyContent(document, test.format, test.data)
+		if !test.mismatch {
+			assert.NoError(t, err, i)
+			continue
+		}
+		var mismatchErr *ContentMismatchError
+		assert.True(t, errors.As(err, &mismatchErr), i)
+		assert.True(t, errors.Is(err, ErrContentMismatch), i)
+		assert.Equal(t, test.format, mismatchErr.Format, i)
+	}
+
+	err := VerifyContent(document, signicat.FileFormatXades, nil)
+	assert.False(t, errors.Is(err, ErrContentMismatch))
+	assert.True(t, errors.Is(err, ErrNotVerifiable))
+
+	err = VerifyContent(&signicat.Document{}, signicat.FileFormatPades, contract)
+	assert.Error(t, err)
+}
+
+func TestVerifyContent_NotVerifiable(t *testing.T) {
+	contract := []byte("%PDF-1.7 contract")
+	sum := sha256.Sum256(contract)
+	digest := &signicat.ContentDigest{Algorithm: signicat.DigestAlgorithmSHA256, Value: sum[:], Size: int64(len(contract))}
+
+	// Swedish BankID signs its own signed data, not the document.
+	signedData := []byte("<bankIdSignedData>signed</bankIdSignedData>")
+	anna, ca := testpki.Chain("Anna Andersson", "198112289874")
+	bankID := []byte(`<sig:Signature xmlns:sig="http://www.example.com/bankid">` + testpki.Signature(&testpki.SignatureOptions{
+		ID: "bankid", Document: signedData, DocumentURI: "#bidSignedData", Certificates: []*x509.Certificate{anna, ca},
+	}) + `<bankIdSignedData Id="bidSignedData"/></sig:Signature>`)
+	document := &signicat.Document{
+		ContentDigest: digest,
+		Signers: []*signicat.SignerResponse{
+			{DocumentSignature: &signicat.DocumentSignature{SignatureMethod: signicat.SignatureMethodSeBankID}},
+		},
+	}
+	err := VerifyContent(document, signicat.FileFormatNative, bankID)
+	assert.True(t, errors.Is(err, ErrNotVerifiable))
+	assert.False(t, errors.Is(err, ErrContentMismatch))
+	assert.EqualError(t, err, "signed file can't be checked against the original content: native se_bankid signatures are not over the document")
+
+	tests := []struct {
+		dataToSign *signicat.DataToSign
+		err        string
+	}{
+		{
+			dataToSign: &signicat.DataToSign{FileName: "contract.docx", ConvertToPDF: true},
+			err:        "signed file can't be checked against the original content: the original file was converted to PDF",
+		},
+		{
+			dataToSign: &signicat.DataToSign{FileName: "contract.txt"},
+			err:        "signed file can't be checked against the original content: the original file contract.txt is not a PDF",
+		},
+	}
+
+	for _, test := range tests {
+		document := &signicat.Document{ContentDigest: digest, DataToSign: test.dataToSign}
+		err := VerifyContent(document, signicat.FileFormatPades, []byte("%PDF-1.7 converted contract"))
+		assert.True(t, errors.Is(err, ErrNotVerifiable), test.err)
+		assert.EqualError(t, err, test.err)
+	}
+
+	document = &signicat.Document{ContentDigest: digest, DataToSign: &signicat.DataToSign{FileName: "Contract.PDF"}}
+	assert.NoError(t, VerifyContent(document, signicat.FileFormatPades, append(append([]byte{}, contract...), "\n%%EOF"...)))
+}
@@ -0,0 +1,467 @@
+// Package evidence exports the evidence of a signed document into a single zip file, and verifies that an exported bundle hasn't
+// been altered since.
+//
+// A bundle holds:
+//
+//	document.json    the document, as retrieved from Signicat
+//	signatures.json  the signature of each signer who has signed
+//	events.json      the audit trail of the document
+//	files/           every file format available for the document
+//	validation.json  a report from reading the signatures in the signed files locally
+//	manifest.json    the size and SHA-256 hash of each of the files above
+//
+// The manifest is stored in the bundle unsigned, so anyone who changes a file can change the manifest to match. Export returns
+// the SHA-256 hash of the manifest in Manifest.Digest, which must be stored apart from the bundle, eg. in a database, and given
+// to Verify.
+package evidence
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/larwef/signicat"
+	"github.com/larwef/signicat/native"
+)
+
+// Names of the files in a bundle.
+const (
+	ManifestName   = "manifest.json"
+	DocumentName   = "document.json"
+	SignaturesName = "signatures.json"
+	EventsName     = "events.json"
+	ReportName     = "validation.json"
+	FilesDir       = "files/"
+)
+
+// ErrModified is returned, wrapped in a *VerificationError, when a bundle doesn't match its manifest.
+var ErrModified = errors.New("evidence bundle has been modified")
+
+// Formats are the file formats put in a bundle, when they are available.
+var Formats = []string{
+	signicat.FileFormatUnsigned,
+	signicat.FileFormatNative,
+	signicat.FileFormatStandardPackaging,
+	signicat.FileFormatPades,
+	signicat.FileFormatXades,
+}
+
+// Extensions of the files in FilesDir, keyed by file format. The unsigned file keeps the extension of the original file name.
+var extensions = map[string]string{
+	signicat.FileFormatNative:            ".xml",
+	signicat.FileFormatStandardPackaging: ".xml",
+	signicat.FileFormatPades:             ".pdf",
+	signicat.FileFormatXades:             ".xml",
+}
+
+// Manifest lists the files in a bundle.
+type Manifest struct {
+	DocumentID string           `json:"documentId"`
+	Created    time.Time        `json:"created"`
+	Files      []*ManifestEntry `json:"files"`
+	// Digest is the hex encoded SHA-256 hash of manifest.json. It isn't part of the manifest itself.
+	Digest string `json:"-"`
+}
+
+// ManifestEntry is a file in a bundle.
+type ManifestEntry struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	// SHA256 is the hex encoded SHA-256 hash of the file.
+	SHA256 string `json:"sha256"`
+}
+
+// SignerSignature is the signature of a signer, as reported by Signicat.
+type SignerSignature struct {
+	SignerID          string                      `json:"signerId"`
+	ExternalSignerID  string                      `json:"externalSignerId,omitempty"`
+	DocumentSignature *signicat.DocumentSignature `json:"documentSignature"`
+}
+
+// Exporter exports evidence bundles.
+type Exporter struct {
+	Signature *signicat.SignatureService
+	// Natives decodes the native file for the validation report. Defaults to native.DefaultRegistry.
+	Natives *native.Registry
+	// Now returns the creation time of a bundle. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Export writes the evidence bundle of a signed document to w as a zip file, and returns its manifest. Store Manifest.Digest apart
+// from the bundle to verify it later. Returns a *signicat.StatusError if the document isn't signed.
+func (e *Exporter) Export(ctx context.Context, documentID string, w io.Writer) (*Manifest, error) {
+	document, err := e.Signature.RetrieveDocument(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	status := document.Status
+	if status == nil {
+		if status, err = e.Signature.RetrieveDocumentStatus(ctx, documentID); err != nil {
+			return nil, err
+		}
+	}
+	if status.DocumentStatus != signicat.DocumentStatusSigned {
+		return nil, &signicat.StatusError{Operation: "export evidence for", DocumentID: documentID, DocumentStatus: status.DocumentStatus}
+	}
+
+	events, err := e.Signature.ListDocumentEvents(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now
+	if e.Now != nil {
+		now = e.Now
+	}
+	b := &bundleWriter{
+		zip:      zip.NewWriter(w),
+		manifest: &Manifest{DocumentID: documentID, Created: now().UTC()},
+	}
+
+	if err := b.writeJSON(DocumentName, document); err != nil {
+		return nil, err
+	}
+	if err := b.writeJSON(SignaturesName, signerSignatures(document)); err != nil {
+		return nil, err
+	}
+	if err := b.writeJSON(EventsName, events); err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	for _, format := range Formats {
+		if !status.CanDownload(format) {
+			continue
+		}
+
+		entry, err := b.create(FilesDir + fileName(document, format))
+		if err != nil {
+			return nil, err
+		}
+		// The files the report is made from are kept. The rest are only streamed into the bundle.
+		var dst io.Writer = entry
+		var buf bytes.Buffer
+		keep := format != signicat.FileFormatPades && format != signicat.FileFormatXades
+		if keep {
+			dst = io.MultiWriter(entry, &buf)
+		}
+		if err := e.Signature.RetrieveFile(ctx, documentID, format, false, dst); err != nil {
+			return nil, fmt.Errorf("retrieving %s file: %w", format, err)
+		}
+		if keep {
+			files[format] = buf.Bytes()
+		}
+	}
+
+	natives := e.Natives
+	if natives == nil {
+		natives = native.DefaultRegistry
+	}
+	if err := b.writeJSON(ReportName, newReport(document, files, natives)); err != nil {
+		return nil, err
+	}
+
+	if err := b.close(); err != nil {
+		return nil, err
+	}
+
+	return b.manifest, nil
+}
+
+// ExportFile writes the evidence bundle of a signed document to a new file with the given name. See Export. The file must not
+// exist, and is removed if exporting fails, so no partial bundle is left behind.
+func (e *Exporter) ExportFile(ctx context.Context, documentID, name string) (manifest *Manifest, err error) {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			manifest = nil
+			os.Remove(name)
+		}
+	}()
+
+	return e.Export(ctx, documentID, f)
+}
+
+// fileName returns the name of a file format in FilesDir.
+func fileName(document *signicat.Document, format string) string {
+	ext, ok := extensions[format]
+	if !ok && document.DataToSign != nil {
+		ext = path.Ext(document.DataToSign.FileName)
+	}
+
+	return format + ext
+}
+
+func signerSignatures(document *signicat.Document) []*SignerSignature {
+	signatures := []*SignerSignature{}
+	for _, signer := range document.Signers {
+		if signer.DocumentSignature == nil {
+			continue
+		}
+		signatures = append(signatures, &SignerSignature{
+			SignerID:          signer.ID,
+			ExternalSignerID:  signer.ExternalSignerID,
+			DocumentSignature: signer.DocumentSignature,
+		})
+	}
+
+	return signatures
+}
+
+// bundleWriter writes files to a zip, and adds them to the manifest.
+type bundleWriter struct {
+	zip      *zip.Writer
+	manifest *Manifest
+	current  *hashingWriter
+}
+
+// create starts a new file in the bundle. The file is written until the next call to create or close.
+func (b *bundleWriter) create(name string) (io.Writer, error) {
+	b.finish()
+
+	w, err := b.zip.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: b.manifest.Created})
+	if err != nil {
+		return nil, err
+	}
+	b.current = &hashingWriter{name: name, w: w, hash: sha256.New()}
+
+	return b.current, nil
+}
+
+func (b *bundleWriter) writeJSON(name string, v interface{}) error {
+	w, err := b.create(name)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+// finish adds the current file to the manifest.
+func (b *bundleWriter) finish() {
+	if b.current == nil {
+		return
+	}
+
+	b.manifest.Files = append(b.manifest.Files, &ManifestEntry{
+		Name:   b.current.name,
+		Size:   b.current.size,
+		SHA256: hex.EncodeToString(b.current.hash.Sum(nil)),
+	})
+	b.current = nil
+}
+
+// close writes the manifest and closes the zip.
+func (b *bundleWriter) close() error {
+	b.finish()
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(b.manifest); err != nil {
+		return err
+	}
+	b.manifest.Digest = digest(buf.Bytes())
+
+	w, err := b.zip.CreateHeader(&zip.FileHeader{Name: ManifestName, Method: zip.Deflate, Modified: b.manifest.Created})
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	return b.zip.Close()
+}
+
+type hashingWriter struct {
+	name string
+	w    io.Writer
+	hash hash.Hash
+	size int64
+}
+
+func (h *hashingWriter) Write(p []byte) (int, error) {
+	n, err := h.w.Write(p)
+	h.hash.Write(p[:n])
+	h.size += int64(n)
+
+	return n, err
+}
+
+// VerificationError lists how a bundle differs from its manifest.
+type VerificationError struct {
+	Problems []string
+}
+
+func (e *VerificationError) Error() string {
+	return ErrModified.Error() + ": " + strings.Join(e.Problems, "; ")
+}
+
+// Unwrap returns ErrModified.
+func (e *VerificationError) Unwrap() error {
+	return ErrModified
+}
+
+// Verify checks that the manifest of the bundle has the digest returned by Export, that every file in the bundle is listed in the
+// manifest with the same size and hash, that no file in the manifest is missing, and that no name is used by more than one file. Returns the manifest, and a
+// *VerificationError if the bundle has been modified.
+func Verify(r io.ReaderAt, size int64, manifestDigest string) (*Manifest, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return verify(zr, manifestDigest)
+}
+
+// VerifyFile verifies the bundle in the named file. See Verify.
+func VerifyFile(name, manifestDigest string) (*Manifest, error) {
+	zr, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	return verify(&zr.Reader, manifestDigest)
+}
+
+func verify(zr *zip.Reader, manifestDigest string) (*Manifest, error) {
+	if manifestDigest == "" {
+		return nil, errors.New("no manifest digest to verify the bundle with")
+	}
+
+	// A zip can hold several files with the same name, and readers differ in which of them they extract.
+	counts := make(map[string]int)
+	for _, f := range zr.File {
+		counts[f.Name]++
+	}
+	if counts[ManifestName] > 1 {
+		return nil, &VerificationError{Problems: []string{duplicate(ManifestName, counts[ManifestName])}}
+	}
+
+	var manifest *Manifest
+	for _, f := range zr.File {
+		if f.Name != ManifestName {
+			continue
+		}
+		b, err := readFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading manifest: %v", err)
+		}
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			return nil, fmt.Errorf("reading manifest: %v", err)
+		}
+		manifest.Digest = digest(b)
+	}
+	if manifest == nil {
+		return nil, &VerificationError{Problems: []string{"no manifest"}}
+	}
+	if !strings.EqualFold(manifest.Digest, manifestDigest) {
+		// The files can't be trusted to match the manifest either.
+		return manifest, &VerificationError{Problems: []string{ManifestName + " doesn't match the manifest digest"}}
+	}
+
+	listed := make(map[string]*ManifestEntry)
+	for _, entry := range manifest.Files {
+		listed[entry.Name] = entry
+	}
+
+	var problems []string
+	found := make(map[string]bool)
+	for _, f := range zr.File {
+		if f.Name == ManifestName {
+			continue
+		}
+		if counts[f.Name] > 1 {
+			if !found[f.Name] {
+				problems = append(problems, duplicate(f.Name, counts[f.Name]))
+				found[f.Name] = true
+			}
+			continue
+		}
+		entry, ok := listed[f.Name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s is not in the manifest", f.Name))
+			continue
+		}
+		found[f.Name] = true
+
+		size, sum, err := hashFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %v", f.Name, err)
+		}
+		if size != entry.Size || sum != entry.SHA256 {
+			problems = append(problems, fmt.Sprintf("%s doesn't match the manifest", f.Name))
+		}
+	}
+	for _, entry := range manifest.Files {
+		if !found[entry.Name] {
+			problems = append(problems, fmt.Sprintf("%s is missing", entry.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return manifest, &VerificationError{Problems: problems}
+	}
+
+	return manifest, nil
+}
+
+func duplicate(name string, count int) string {
+	return fmt.Sprintf("%s is in the bundle %d times", name, count)
+}
+
+func readFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return ioutil.ReadAll(rc)
+}
+
+func digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hashFile(f *zip.File) (int64, string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return 0, "", err
+	}
+	defer rc.Close()
+
+	hash := sha256.New()
+	n, err := io.Copy(hash, rc)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return n, hex.EncodeToString(hash.Sum(nil)), nil
+}
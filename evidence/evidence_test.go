@@ -0,0 +1,359 @@
+package evidence
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/larwef/signicat"
+	"github.com/larwef/signicat/internal/testpki"
+	"github.com/stretchr/testify/assert"
+)
+
+var created = time.Date(2020, 6, 27, 8, 0, 0, 0, time.UTC)
+
+// setup serves a signed document, and returns an exporter using it.
+func setup(t *testing.T, document string) (*Exporter, func()) {
+	contract := []byte("%PDF-1.7 contract")
+	ola, ca := testpki.Chain("Ola Nordmann", "9578-6000-4-123456")
+	signed := time.Date(2020, 6, 26, 12, 0, 0, 0, time.UTC)
+
+	files := map[string]string{
+		signicat.FileFormatUnsigned: string(contract),
+		signicat.FileFormatStandardPackaging: `<sdo:SDO xmlns:sdo="http://www.signicat.com/sdo">` + testpki.Signature(&testpki.SignatureOptions{
+			ID: "ola", Document: contract, Certificates: []*x509.Certificate{ola, ca}, SigningTime: &signed,
+		}) + `</sdo:SDO>`,
+		signicat.FileFormatNative: `<SEIDSDO><CMSSignature>` +
+			base64.StdEncoding.EncodeToString(testpki.SignedData(contract, signed, ola, ca)) + `</CMSSignature></SEIDSDO>`,
+		signicat.FileFormatPades: "%PDF-1.7 signed contract",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/signature/documents/someDocumentId", func(res http.ResponseWriter, req *http.Request) {
+		if _, err := io.WriteString(res, document); err != nil {
+			t.Fatal(err)
+		}
+	})
+	mux.HandleFunc("/signature/documents/someDocumentId/events", func(res http.ResponseWriter, req *http.Request) {
+		if _, err := io.WriteString(res, `[{"id":"1","type":"created"},{"id":"2","type":"signed","signerId":"ola"}]`); err != nil {
+			t.Fatal(err)
+		}
+	})
+	mux.HandleFunc("/signature/documents/someDocumentId/files", func(res http.ResponseWriter, req *http.Request) {
+		file, ok := files[req.URL.Query().Get("fileFormat")]
+		if !ok {
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if _, err := io.WriteString(res, file); err != nil {
+			t.Fatal(err)
+		}
+	})
+	server := httptest.NewServer(mux)
+
+	client, err := signicat.NewClientWithURL(&http.Client{}, server.URL)
+	assert.NoError(t, err)
+
+	return &Exporter{Signature: client.Signature, Now: func() time.Time { return created }}, server.Close
+}
+
+const signedDocument = `{
+  "documentId": "someDocumentId",
+  "status": {"documentStatus": "signed", "completedPackages": ["native", "standard_packaging", "pades"]},
+  "dataToSign": {"fileName": "contract.pdf"},
+  "signers": [{
+    "id": "ola",
+    "externalSignerId": "someSigner",
+    "documentSignature": {"signatureMethod": "no_bankid_netcentric", "fullName": "Ola Nordmann", "signatureMethodUniqueId": "9578-6000-4-123456"}
+  }]
+}`
+
+func TestExporter_Export(t *testing.T) {
+	exporter, teardown := setup(t, signedDocument)
+	defer teardown()
+
+	var buf bytes.Buffer
+	manifest, err := exporter.Export(context.Background(), "someDocumentId", &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "someDocumentId", manifest.DocumentID)
+	assert.Equal(t, created, manifest.Created)
+	assert.Len(t, manifest.Digest, 64)
+
+	var names []string
+	for _, entry := range manifest.Files {
+		names = append(names, entry.Name)
+	}
+	assert.Equal(t, []string{
+		DocumentName,
+		SignaturesName,
+		EventsName,
+		FilesDir + "unsigned.pdf",
+		FilesDir + "native.xml",
+		FilesDir + "standard_packaging.xml",
+		FilesDir + "pades.pdf",
+		ReportName,
+	}, names)
+
+	verified, err := Verify(bytes.NewReader(buf.Bytes()), int64(buf.Len()), manifest.Digest)
+	assert.NoError(t, err)
+	assert.Equal(t, manifest, verified)
+
+	var report Report
+	readJSON(t, buf.Bytes(), ReportName, &report)
+	assert.Empty(t, report.Problems)
+	assert.Equal(t, []string{"native", "standard_packaging", "unsigned"}, report.Formats)
+	assert.Len(t, report.Signatures, 2)
+	for _, s := range report.Signatures {
+		assert.Equal(t, "ola", s.SignerID)
+		assert.Equal(t, "9578-6000-4-123456", s.UniqueID)
+		assert.True(t, *s.DocumentMatches, s.Format)
+	}
+
+	var signatures []*SignerSignature
+	readJSON(t, buf.Bytes(), SignaturesName, &signatures)
+	assert.Len(t, signatures, 1)
+	assert.Equal(t, "Ola Nordmann", signatures[0].DocumentSignature.FullName)
+}
+
+func TestExporter_Export_Problems(t *testing.T) {
+	// Signed by someone else than the signature found in the files.
+	exporter, teardown := setup(t, `{
+  "documentId": "someDocumentId",
+  "status": {"documentStatus": "signed", "completedPackages": ["standard_packaging"]},
+  "signers": [{"id": "kari", "documentSignature": {"signatureMethod": "no_bankid_netcentric", "fullName": "Kari Nordmann"}}]
+}`)
+	defer teardown()
+
+	var buf bytes.Buffer
+	_, err := exporter.Export(context.Background(), "someDocumentId", &buf)
+	assert.NoError(t, err)
+
+	var report Report
+	readJSON(t, buf.Bytes(), ReportName, &report)
+	assert.Equal(t, []string{"standard_packaging: no signature found for signer kari"}, report.Problems)
+}
+
+func TestExporter_Export_NotSigned(t *testing.T) {
+	exporter, teardown := setup(t, `{"documentId":"someDocumentId","status":{"documentStatus":"partialsigned"}}`)
+	defer teardown()
+
+	_, err := exporter.Export(context.Background(), "someDocumentId", ioutil.Discard)
+	var statusErr *signicat.StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, signicat.DocumentStatusPartialSigned, statusErr.DocumentStatus)
+}
+
+func TestVerify_Modified(t *testing.T) {
+	exporter, teardown := setup(t, signedDocument)
+	defer teardown()
+
+	var buf bytes.Buffer
+	manifest, err := exporter.Export(context.Background(), "someDocumentId", &buf)
+	assert.NoError(t, err)
+
+	modified := rewrite(t, buf.Bytes(), map[string]string{
+		FilesDir + "pades.pdf": "%PDF-1.7 another contract",
+		EventsName:             "",
+		"extra.txt":            "extra",
+	})
+
+	_, err = Verify(bytes.NewReader(modified), int64(len(modified)), manifest.Digest)
+	assert.True(t, errors.Is(err, ErrModified))
+	var verificationErr *VerificationError
+	assert.True(t, errors.As(err, &verificationErr))
+	assert.Equal(t, []string{
+		"events.json is missing",
+		"extra.txt is not in the manifest",
+		"files/pades.pdf doesn't match the manifest",
+	}, verificationErr.Problems)
+
+	dir, err := ioutil.TempDir("", "evidence")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "bundle.zip")
+	assert.NoError(t, ioutil.WriteFile(name, modified, 0600))
+	_, err = VerifyFile(name, manifest.Digest)
+	assert.True(t, errors.Is(err, ErrModified))
+
+	_, err = VerifyFile(filepath.Join(dir, "missing.zip"), manifest.Digest)
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = Verify(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "")
+	assert.EqualError(t, err, "no manifest digest to verify the bundle with")
+}
+
+func TestVerify_RewrittenManifest(t *testing.T) {
+	exporter, teardown := setup(t, signedDocument)
+	defer teardown()
+
+	var buf bytes.Buffer
+	manifest, err := exporter.Export(context.Background(), "someDocumentId", &buf)
+	assert.NoError(t, err)
+
+	// Replace a file, and update its entry in the manifest to match.
+	pades := "%PDF-1.7 another contract"
+	rewritten := *manifest
+	rewritten.Files = nil
+	for _, entry := range manifest.Files {
+		e := *entry
+		if e.Name == FilesDir+"pades.pdf" {
+			e.Size, e.SHA256 = int64(len(pades)), digest([]byte(pades))
+		}
+		rewritten.Files = append(rewritten.Files, &e)
+	}
+	b, err := json.Marshal(&rewritten)
+	assert.NoError(t, err)
+
+	modified := rewrite(t, buf.Bytes(), map[string]string{FilesDir + "pades.pdf": pades, ManifestName: string(b)})
+
+	_, err = Verify(bytes.NewReader(modified), int64(len(modified)), manifest.Digest)
+	assert.True(t, errors.Is(err, ErrModified))
+	assert.EqualError(t, err, "evidence bundle has been modified: manifest.json doesn't match the manifest digest")
+}
+
+func TestVerify_Duplicates(t *testing.T) {
+	exporter, teardown := setup(t, signedDocument)
+	defer teardown()
+
+	var buf bytes.Buffer
+	manifest, err := exporter.Export(context.Background(), "someDocumentId", &buf)
+	assert.NoError(t, err)
+
+	// A second file with the same name as a file in the manifest, which a reader might extract instead.
+	modified := addFile(t, buf.Bytes(), FilesDir+"pades.pdf", "%PDF-1.7 another contract")
+	_, err = Verify(bytes.NewReader(modified), int64(len(modified)), manifest.Digest)
+	assert.True(t, errors.Is(err, ErrModified))
+	assert.EqualError(t, err, "evidence bundle has been modified: files/pades.pdf is in the bundle 2 times")
+
+	// A forged manifest next to the original one.
+	modified = addFile(t, buf.Bytes(), ManifestName, `{"documentId":"someDocumentId","files":[]}`)
+	_, err = Verify(bytes.NewReader(modified), int64(len(modified)), manifest.Digest)
+	assert.True(t, errors.Is(err, ErrModified))
+	assert.EqualError(t, err, "evidence bundle has been modified: manifest.json is in the bundle 2 times")
+}
+
+func TestExporter_ExportFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "evidence")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	exporter, teardown := setup(t, signedDocument)
+	defer teardown()
+
+	name := filepath.Join(dir, "bundle.zip")
+	manifest, err := exporter.ExportFile(context.Background(), "someDocumentId", name)
+	assert.NoError(t, err)
+	_, err = VerifyFile(name, manifest.Digest)
+	assert.NoError(t, err)
+
+	// An existing file is neither overwritten nor removed.
+	_, err = exporter.ExportFile(context.Background(), "someDocumentId", name)
+	assert.True(t, os.IsExist(err))
+	_, err = VerifyFile(name, manifest.Digest)
+	assert.NoError(t, err)
+
+	// Fails while streaming the files into the bundle.
+	failing, teardown := setup(t, `{"documentId":"someDocumentId","status":{"documentStatus":"signed","completedPackages":["xades"]}}`)
+	defer teardown()
+
+	name = filepath.Join(dir, "failed.zip")
+	manifest, err = failing.ExportFile(context.Background(), "someDocumentId", name)
+	assert.Error(t, err)
+	assert.Nil(t, manifest)
+	_, err = os.Stat(name)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func readJSON(t *testing.T, bundle []byte, name string, v interface{}) {
+	zr, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	assert.NoError(t, err)
+
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		defer rc.Close()
+
+		assert.NoError(t, json.NewDecoder(rc).Decode(v))
+		return
+	}
+	t.Fatalf("%s not found", name)
+}
+
+// rewrite copies a bundle with the files in changes replaced. An empty content removes the file.
+func rewrite(t *testing.T, bundle []byte, changes map[string]string) []byte {
+	zr, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		content, changed := changes[f.Name]
+		if changed {
+			delete(changes, f.Name)
+			if content == "" {
+				continue
+			}
+		} else {
+			rc, err := f.Open()
+			assert.NoError(t, err)
+			b, err := ioutil.ReadAll(rc)
+			assert.NoError(t, err)
+			rc.Close()
+			content = string(b)
+		}
+		w, err := zw.Create(f.Name)
+		assert.NoError(t, err)
+		_, err = io.WriteString(w, content)
+		assert.NoError(t, err)
+	}
+	for name, content := range changes {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = io.WriteString(w, content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+// addFile copies a bundle with another file added, also if a file with the same name exists.
+func addFile(t *testing.T, bundle []byte, name, content string) []byte {
+	zr, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		w, err := zw.Create(f.Name)
+		assert.NoError(t, err)
+		_, err = io.Copy(w, rc)
+		assert.NoError(t, err)
+		rc.Close()
+	}
+	w, err := zw.Create(name)
+	assert.NoError(t, err)
+	_, err = io.WriteString(w, content)
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
@@ -0,0 +1,196 @@
+package evidence
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/larwef/signicat"
+	"github.com/larwef/signicat/native"
+	"github.com/larwef/signicat/sdo"
+)
+
+// Report is the result of reading the signatures in the signed files of a document locally. The signatures are read, not
+// cryptographically verified.
+type Report struct {
+	// Formats are the file formats in the bundle.
+	Formats    []string           `json:"formats"`
+	Signatures []*SignatureReport `json:"signatures"`
+	// Problems are the inconsistencies found, eg. a signer without a signature in the signed files.
+	Problems []string `json:"problems,omitempty"`
+}
+
+// SignatureReport is a signature found in a signed file.
+type SignatureReport struct {
+	// Format is the file format the signature was read from.
+	Format string `json:"format"`
+	// SignerID is the signer with the same name or unique ID in its DocumentSignature, if any.
+	SignerID    string       `json:"signerId,omitempty"`
+	Name        string       `json:"name,omitempty"`
+	UniqueID    string       `json:"uniqueId,omitempty"`
+	Certificate *Certificate `json:"certificate,omitempty"`
+	SignedTime  *time.Time   `json:"signedTime,omitempty"`
+	// DigestAlgorithm and Digest are the hash of the signed data, hex encoded.
+	DigestAlgorithm string `json:"digestAlgorithm,omitempty"`
+	Digest          string `json:"digest,omitempty"`
+	// DocumentMatches tells whether the digest is the digest of the unsigned file. It is nil when it can't be checked, which
+	// includes the native signatures of signature methods which sign data other than the document, eg. Swedish BankID.
+	DocumentMatches *bool `json:"documentMatches,omitempty"`
+}
+
+// Certificate is a summary of a signer certificate.
+type Certificate struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serialNumber"`
+	NotBefore    time.Time `json:"notBefore"`
+	NotAfter     time.Time `json:"notAfter"`
+}
+
+func newCertificate(c *x509.Certificate) *Certificate {
+	if c == nil {
+		return nil
+	}
+
+	return &Certificate{
+		Subject:      c.Subject.String(),
+		Issuer:       c.Issuer.String(),
+		SerialNumber: c.SerialNumber.String(),
+		NotBefore:    c.NotBefore,
+		NotAfter:     c.NotAfter,
+	}
+}
+
+// newReport reads the signatures in files, keyed by file format.
+func newReport(document *signicat.Document, files map[string][]byte, natives *native.Registry) *Report {
+	report := &Report{Signatures: []*SignatureReport{}}
+	for format := range files {
+		report.Formats = append(report.Formats, format)
+	}
+	sort.Strings(report.Formats)
+
+	unsigned, hasUnsigned := files[signicat.FileFormatUnsigned]
+
+	if data, ok := files[signicat.FileFormatStandardPackaging]; ok {
+		if parsed, err := sdo.Parse(data); err != nil {
+			report.problem("%s: %v", signicat.FileFormatStandardPackaging, err)
+		} else {
+			signatures := sdoReports(parsed, unsigned, hasUnsigned)
+			for _, sr := range signatures {
+				if sr.DocumentMatches != nil && !*sr.DocumentMatches {
+					report.problem("%s: the signature of %s is not over the unsigned file", signicat.FileFormatStandardPackaging, sr.Name)
+				}
+			}
+			report.addSignatures(document, signicat.FileFormatStandardPackaging, signatures)
+		}
+	}
+
+	if data, ok := files[signicat.FileFormatNative]; ok {
+		method := signatureMethod(document)
+		if decoded, err := natives.Decode(method, data); err != nil {
+			report.problem("%s: %v", signicat.FileFormatNative, err)
+		} else {
+			overDocument := hasUnsigned && documentDigestMethods[method]
+			report.addSignatures(document, signicat.FileFormatNative, nativeReports(decoded, unsigned, overDocument))
+		}
+	}
+
+	return report
+}
+
+func sdoReports(parsed *sdo.SDO, unsigned []byte, hasUnsigned bool) []*SignatureReport {
+	var signatures []*SignatureReport
+	for _, s := range parsed.Signatures {
+		ds := s.DocumentSignature()
+		sr := &SignatureReport{
+			Format:      signicat.FileFormatStandardPackaging,
+			Name:        ds.FullName,
+			UniqueID:    ds.SignatureMethodUniqueID,
+			Certificate: newCertificate(s.Certificate),
+			SignedTime:  ds.SignedTime,
+		}
+		if s.DocumentDigest != nil {
+			sr.DigestAlgorithm = s.DocumentDigest.Algorithm
+			sr.Digest = hex.EncodeToString(s.DocumentDigest.Value)
+			if hasUnsigned {
+				matches := s.DocumentDigest.Matches(unsigned)
+				sr.DocumentMatches = &matches
+			}
+		}
+		signatures = append(signatures, sr)
+	}
+
+	return signatures
+}
+
+// nativeReports makes the reports of native signatures. The digests are compared to the unsigned file if compare is set.
+func nativeReports(decoded []*native.Signature, unsigned []byte, compare bool) []*SignatureReport {
+	var signatures []*SignatureReport
+	for _, s := range decoded {
+		sr := &SignatureReport{
+			Format:      signicat.FileFormatNative,
+			Name:        s.Name,
+			UniqueID:    s.UniqueID,
+			Certificate: newCertificate(s.Certificate),
+			SignedTime:  s.SignedTime,
+			Digest:      hex.EncodeToString(s.Digest),
+		}
+		if s.DigestAlgorithm != 0 {
+			sr.DigestAlgorithm = s.DigestAlgorithm.String()
+			if compare && s.DigestAlgorithm.Available() {
+				hash := s.DigestAlgorithm.New()
+				hash.Write(unsigned)
+				matches := bytes.Equal(hash.Sum(nil), s.Digest)
+				sr.DocumentMatches = &matches
+			}
+		}
+		signatures = append(signatures, sr)
+	}
+
+	return signatures
+}
+
+// addSignatures adds the signatures found in a file format, and reports signers without a signature in it.
+func (r *Report) addSignatures(document *signicat.Document, format string, signatures []*SignatureReport) {
+	for _, signer := range document.Signers {
+		ds := signer.DocumentSignature
+		if ds == nil {
+			continue
+		}
+
+		found := false
+		for _, sr := range signatures {
+			if sr.SignerID != "" {
+				continue
+			}
+			if (ds.SignatureMethodUniqueID != "" && sr.UniqueID == ds.SignatureMethodUniqueID) || (ds.FullName != "" && sr.Name == ds.FullName) {
+				sr.SignerID = signer.ID
+				found = true
+				break
+			}
+		}
+		if !found {
+			r.problem("%s: no signature found for signer %s", format, signer.ID)
+		}
+	}
+
+	r.Signatures = append(r.Signatures, signatures...)
+}
+
+func (r *Report) problem(format string, a ...interface{}) {
+	r.Problems = append(r.Problems, fmt.Sprintf(format, a...))
+}
+
+// signatureMethod returns the signature method used by the signers who have signed. The native file is in the format of it.
+func signatureMethod(document *signicat.Document) string {
+	for _, signer := range document.Signers {
+		if signer.DocumentSignature != nil && signer.DocumentSignature.SignatureMethod != "" {
+			return signer.DocumentSignature.SignatureMethod
+		}
+	}
+
+	return signicat.SignatureMethodUnknown
+}
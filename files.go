@@ -0,0 +1,191 @@
+package signicat
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/larwef/signicat/internal/sniff"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+)
+
+// Content types detected by DetectContentType.
+const (
+	ContentTypePDF     = sniff.PDF
+	ContentTypeDOCX    = sniff.DOCX
+	ContentTypeXML     = sniff.XML
+	ContentTypeText    = sniff.Text
+	ContentTypeUnknown = sniff.Unknown
+)
+
+// DetectContentType returns the content type of a file from its first bytes, which is one of the ContentType constants. The file
+// name is only used to tell DOCX files from other zip files.
+func DetectContentType(data []byte, fileName string) string {
+	return sniff.ContentType(data, fileName)
+}
+
+// UploadFileOptions holds the options used when uploading a file.
+type UploadFileOptions struct {
+	FileName string
+	// ContentType is detected from the file if empty.
+	ContentType string
+	// Multipart sends the file as multipart/form-data instead of as the raw request body.
+	Multipart bool
+	// Progress is called as the file is sent, with the number of bytes sent so far. total is -1 if the size of the file is unknown.
+	Progress func(sent, total int64)
+}
+
+// FileReference is an uploaded file. ID is used as DataToSign.FileID or AttachmentRequest.FileID.
+type FileReference struct {
+	ID          string `json:"id"`
+	FileName    string `json:"fileName,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+}
+
+// UploadFile uploads a file, without base64 encoding it, for use in a later request. The file is streamed from r.
+func (s *SignatureService) UploadFile(ctx context.Context, r io.Reader, opts *UploadFileOptions) (*FileReference, error) {
+	if opts == nil {
+		opts = &UploadFileOptions{}
+	}
+
+	// Look at the size before wrapping r, which hides it.
+	total, ok := remaining(r)
+	if !ok {
+		total = -1
+	}
+
+	br := bufio.NewReaderSize(r, sniff.Len)
+	contentType := opts.ContentType
+	if contentType == "" {
+		head, err := br.Peek(sniff.Len)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		contentType = sniff.ContentType(head, opts.FileName)
+	}
+
+	var body io.Reader = br
+	if opts.Progress != nil {
+		body = &progressReader{r: br, total: total, progress: opts.Progress}
+	}
+
+	var req *http.Request
+	var err error
+	if opts.Multipart {
+		req, err = s.newMultipartUpload(body, opts.FileName, contentType)
+	} else {
+		req, err = s.newBinaryUpload(body, opts.FileName, contentType, total)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(FileReference)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *SignatureService) newBinaryUpload(body io.Reader, fileName, contentType string, length int64) (*http.Request, error) {
+	u, err := url.Parse("/signature/files/binary")
+	if err != nil {
+		return nil, err
+	}
+	params := u.Query()
+	params.Set("fileName", fileName)
+	u.RawQuery = params.Encode()
+
+	req, err := s.client.NewRequest(http.MethodPost, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = length
+	req.Header.Set("Content-Type", contentType)
+
+	return req, nil
+}
+
+func (s *SignatureService) newMultipartUpload(body io.Reader, fileName, contentType string) (*http.Request, error) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	req, err := s.client.NewRequest(http.MethodPost, "/signature/files/multipart", pr)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, fileName))
+	header.Set("Content-Type", contentType)
+
+	// The pipe is closed by the http client when the request is done, which ends the goroutine.
+	go func() {
+		part, err := mw.CreatePart(header)
+		if err == nil {
+			_, err = io.Copy(part, body)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return req, nil
+}
+
+// progressReader reports the number of bytes read to a callback.
+type progressReader struct {
+	r        io.Reader
+	sent     int64
+	total    int64
+	progress func(sent, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.sent += int64(n)
+		p.progress(p.sent, p.total)
+	}
+
+	return n, err
+}
+
+// AddAttachment adds an uploaded file to a document as an attachment.
+func (s *SignatureService) AddAttachment(ctx context.Context, documentID string, attachmentReq *AttachmentRequest) (*Attachment, error) {
+	u := fmt.Sprintf("/signature/documents/%s/attachments", documentID)
+	req, err := s.client.NewRequest(http.MethodPost, u, attachmentReq)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(Attachment)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// AttachmentRequest is the request body used to add an attachment to a document. FileID is the ID of a FileReference.
+type AttachmentRequest struct {
+	FileID      string `json:"fileId"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Attachment is a file attached to a document.
+type Attachment struct {
+	ID          string `json:"id,omitempty"`
+	FileID      string `json:"fileId,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	FileName    string `json:"fileName,omitempty"`
+}
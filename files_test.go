@@ -0,0 +1,109 @@
+package signicat
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSignatureService_UploadFile(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	content := "%PDF-1.4\n%uploaded"
+	mux.HandleFunc("/signature/files/binary", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, ContentTypePDF, req.Header.Get("Content-Type"))
+		assert.Equal(t, "contract.pdf", req.URL.Query().Get("fileName"))
+		assert.Equal(t, int64(len(content)), req.ContentLength)
+
+		b, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(b))
+
+		if _, err := io.WriteString(res, `{"id":"someFileId","fileName":"contract.pdf"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	var progress []int64
+	file, err := client.Signature.UploadFile(context.Background(), strings.NewReader(content), &UploadFileOptions{
+		FileName: "contract.pdf",
+		Progress: func(sent, total int64) {
+			assert.Equal(t, int64(len(content)), total)
+			progress = append(progress, sent)
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "someFileId", file.ID)
+	assert.Equal(t, int64(len(content)), progress[len(progress)-1])
+}
+
+func TestSignatureService_UploadFile_Multipart(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	content := "<?xml version=\"1.0\"?><contract/>"
+	mux.HandleFunc("/signature/files/multipart", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+
+		file, header, err := req.FormFile("file")
+		assert.NoError(t, err)
+		assert.Equal(t, "contract.xml", header.Filename)
+		assert.Equal(t, ContentTypeXML, header.Header.Get("Content-Type"))
+
+		b, err := ioutil.ReadAll(file)
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(b))
+
+		if _, err := io.WriteString(res, `{"id":"someFileId"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	// Hide the size of the content.
+	r := io.MultiReader(strings.NewReader(content))
+	var total int64
+	file, err := client.Signature.UploadFile(context.Background(), r, &UploadFileOptions{
+		FileName:  "contract.xml",
+		Multipart: true,
+		Progress: func(sent, t int64) {
+			total = t
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "someFileId", file.ID)
+	assert.Equal(t, int64(-1), total)
+}
+
+func TestSignatureService_AddAttachment(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/signature/documents/someDocumentId/attachments", req.URL.Path)
+
+		var body AttachmentRequest
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		assert.Equal(t, "someFileId", body.FileID)
+
+		if _, err := io.WriteString(res, `{"id":"someAttachmentId","fileId":"someFileId"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	attachment, err := client.Signature.AddAttachment(context.Background(), "someDocumentId", &AttachmentRequest{FileID: "someFileId", Title: "Terms"})
+	assert.NoError(t, err)
+	assert.Equal(t, "someAttachmentId", attachment.ID)
+}
+
+func TestDetectContentType(t *testing.T) {
+	assert.Equal(t, ContentTypePDF, DetectContentType([]byte("%PDF-1.7"), ""))
+	assert.Equal(t, ContentTypeText, DetectContentType([]byte("Contract"), "contract.txt"))
+}
@@ -0,0 +1,23 @@
+package signicat
+
+import (
+	"github.com/larwef/signicat/internal/modelgen"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+// Fails if api/signature.json or the overlay has been changed without running go generate.
+func TestGeneratedModelsUpToDate(t *testing.T) {
+	spec, err := ioutil.ReadFile("api/signature.json")
+	assert.NoError(t, err)
+	overlay, err := ioutil.ReadFile("api/signature.overlay.json")
+	assert.NoError(t, err)
+
+	expected, err := modelgen.Generate("signicat", "api/signature.json", spec, overlay)
+	assert.NoError(t, err)
+
+	actual, err := ioutil.ReadFile("signature_models.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual), "signature_models.go is out of date, run go generate")
+}
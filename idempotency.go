@@ -0,0 +1,206 @@
+package signicat
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// lookupTimeout limits the lookup by external ID when the context of the create request is already done.
+const lookupTimeout = 10 * time.Second
+
+// LookupError is returned by CreateDocumentIdempotent when the outcome of creating a document is unknown, and looking it up by
+// its external ID failed too. It isn't known whether the document exists.
+type LookupError struct {
+	// Err is the error from creating the document.
+	Err error
+	// LookupErr is the error from looking it up.
+	LookupErr error
+}
+
+func (e *LookupError) Error() string {
+	return fmt.Sprintf("%v, and looking up the document failed: %v", e.Err, e.LookupErr)
+}
+
+func (e *LookupError) Unwrap() error {
+	return e.Err
+}
+
+// ErrIdempotencyConflict is returned, wrapped, by CreateDocumentIdempotent when the documents found with the idempotency key can't
+// be the document of the request.
+var ErrIdempotencyConflict = errors.New("idempotency key conflict")
+
+// IdempotencyKey returns the key identifying createReq. The key is ExternalID if set, otherwise it is derived from the content of
+// the request, so the same request always gets the same key.
+func IdempotencyKey(createReq *CreateDocumentRequest) (string, error) {
+	if createReq.ExternalID != "" {
+		return createReq.ExternalID, nil
+	}
+	if streamedContent(createReq) != nil {
+		return "", errors.New("idempotency key can't be derived from streamed content, set ExternalID")
+	}
+
+	b, err := json.Marshal(createReq)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// CreateDocumentIdempotent creates a document like CreateDocument, but can safely be called again for the same request. The
+// idempotency key from IdempotencyKey is sent in the Idempotency-Key header, and used as ExternalID if the request has none.
+//
+// If the outcome of creating the document is unknown, eg. because of a timeout or a 5xx response, the document is looked up by
+// its external ID. An existing document is returned instead of creating a duplicate, if it is the only one with the key, has the
+// same title and external signer IDs as the request, and isn't canceled or expired. Otherwise an error wrapping
+// ErrIdempotencyConflict is returned. If there is no document, creating is retried once.
+// A request with DataToSign.Content is only retried if the content implements io.Seeker. The lookup is also done when ctx times
+// out, then with a context of its own limited to 10 seconds, but creating isn't retried. A *LookupError is returned if the lookup
+// fails.
+func (s *SignatureService) CreateDocumentIdempotent(ctx context.Context, createReq *CreateDocumentRequest) (*Document, error) {
+	key, err := IdempotencyKey(createReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't modify the callers request.
+	r := *createReq
+	r.ExternalID = key
+
+	// Streamed content has to be rewound before it is sent again.
+	offset, rewindable := contentOffset(&r)
+
+	for attempt := 0; ; attempt++ {
+		document, digest, err := s.createDocumentWithDigest(ctx, &r, key)
+		if err == nil || !isAmbiguous(err) {
+			return document, err
+		}
+
+		existing, lookupErr := s.lookup(ctx, key)
+		if lookupErr != nil {
+			return nil, &LookupError{Err: err, LookupErr: lookupErr}
+		}
+		if len(existing) > 0 {
+			if conflictErr := checkExisting(&r, existing); conflictErr != nil {
+				return nil, conflictErr
+			}
+			// The digest of the file sent is kept. It is nil if a streamed file wasn't sent in full.
+			existing[0].ContentDigest = digest
+			return existing[0], nil
+		}
+
+		if attempt > 0 || !rewindable || ctx.Err() != nil {
+			return nil, err
+		}
+		if rewindErr := rewind(&r, offset); rewindErr != nil {
+			return nil, err
+		}
+	}
+}
+
+// checkExisting returns an error if the documents found by the external ID of createReq can't be the document of the request.
+// The signers are only compared if the documents found list them.
+func checkExisting(createReq *CreateDocumentRequest, existing []*Document) error {
+	conflict := func(format string, a ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrIdempotencyConflict, fmt.Sprintf(format, a...))
+	}
+
+	if len(existing) > 1 {
+		return conflict("%d documents have the external ID %s", len(existing), createReq.ExternalID)
+	}
+
+	document := existing[0]
+	if document.Status != nil {
+		switch document.Status.DocumentStatus {
+		case DocumentStatusCanceled, DocumentStatusExpired:
+			return conflict("document %s with the external ID %s is %s", document.DocumentID, createReq.ExternalID,
+				document.Status.DocumentStatus)
+		}
+	}
+	if document.Title != createReq.Title {
+		return conflict("document %s has the title %q, not %q", document.DocumentID, document.Title, createReq.Title)
+	}
+
+	if document.Signers != nil {
+		var want, got []string
+		for _, signer := range createReq.Signers {
+			want = append(want, signer.ExternalSignerID)
+		}
+		for _, signer := range document.Signers {
+			got = append(got, signer.ExternalSignerID)
+		}
+		sort.Strings(want)
+		sort.Strings(got)
+		if fmt.Sprint(want) != fmt.Sprint(got) {
+			return conflict("document %s has the signers %v, not %v", document.DocumentID, got, want)
+		}
+	}
+
+	return nil
+}
+
+// lookup lists the documents with an external ID. The lookup isn't tied to ctx if it is done, as the document might have been
+// created before it timed out.
+func (s *SignatureService) lookup(ctx context.Context, externalID string) ([]*Document, error) {
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), lookupTimeout)
+		defer cancel()
+	}
+
+	return s.ListDocuments(ctx, &ListDocumentsOptions{ExternalID: externalID})
+}
+
+func (s *SignatureService) createDocument(ctx context.Context, createReq *CreateDocumentRequest, idempotencyKey string) (*Document, error) {
+	document, _, err := s.createDocumentWithDigest(ctx, createReq, idempotencyKey)
+	return document, err
+}
+
+// createDocumentWithDigest creates a document, and returns the digest of the file sent also when creating it fails.
+func (s *SignatureService) createDocumentWithDigest(ctx context.Context, createReq *CreateDocumentRequest,
+	idempotencyKey string) (*Document, *ContentDigest, error) {
+	createReq, contentDigest := withContentDigest(createReq)
+
+	req, err := s.client.NewRequest(http.MethodPost, "/signature/documents", createReq)
+	if err != nil {
+		return nil, nil, err
+	}
+	if idempotencyKey != "" {
+		req.Header.Set(idempotencyKeyHeader, idempotencyKey)
+	}
+
+	response := new(Document)
+	err = s.client.Do(ctx, req, response)
+	finishStreaming(req)
+	digest := contentDigest()
+	if err != nil {
+		return nil, digest, err
+	}
+	response.ContentDigest = digest
+
+	return response, digest, nil
+}
+
+// isAmbiguous reports whether err leaves it unknown if the request was processed. That is the case for transport errors and
+// timeouts, where the request might have reached the server, and for server errors. Errors from before the request is sent, eg.
+// encoding it, are not ambiguous.
+func isAmbiguous(err error) bool {
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) {
+		return errResp.StatusCode >= http.StatusInternalServerError
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) || errors.Is(err, context.DeadlineExceeded)
+}
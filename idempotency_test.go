@@ -0,0 +1,239 @@
+package signicat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	key, err := IdempotencyKey(&CreateDocumentRequest{ExternalID: "someExternalId"})
+	assert.NoError(t, err)
+	assert.Equal(t, "someExternalId", key)
+
+	first, err := IdempotencyKey(&CreateDocumentRequest{Title: "Contract"})
+	assert.NoError(t, err)
+	second, err := IdempotencyKey(&CreateDocumentRequest{Title: "Contract"})
+	assert.NoError(t, err)
+	other, err := IdempotencyKey(&CreateDocumentRequest{Title: "Other contract"})
+	assert.NoError(t, err)
+
+	assert.Len(t, first, 64)
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, other)
+}
+
+func TestSignatureService_CreateDocumentIdempotent(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var posts int
+	mux.HandleFunc("/signature/documents", func(res http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			posts++
+			assert.Equal(t, "someExternalId", req.Header.Get("Idempotency-Key"))
+			// The document is created, but the response is lost.
+			res.WriteHeader(http.StatusBadGateway)
+		case http.MethodGet:
+			assert.Equal(t, "someExternalId", req.URL.Query().Get("externalId"))
+			if _, err := io.WriteString(res, `[{"documentId":"someDocumentId","externalId":"someExternalId"}]`); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	document, err := client.Signature.CreateDocumentIdempotent(context.Background(), &CreateDocumentRequest{ExternalID: "someExternalId"})
+	assert.NoError(t, err)
+	assert.Equal(t, "someDocumentId", document.DocumentID)
+	assert.Equal(t, 1, posts)
+}
+
+func TestSignatureService_CreateDocumentIdempotent_Retry(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	createReq := &CreateDocumentRequest{Title: "Contract"}
+	key, err := IdempotencyKey(createReq)
+	assert.NoError(t, err)
+
+	var posts, lookups int
+	mux.HandleFunc("/signature/documents", func(res http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			posts++
+			var body CreateDocumentRequest
+			assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+			assert.Equal(t, key, body.ExternalID)
+			if posts == 1 {
+				res.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if _, err := io.WriteString(res, `{"documentId":"someDocumentId"}`); err != nil {
+				t.Fatal(err)
+			}
+		case http.MethodGet:
+			lookups++
+			if _, err := io.WriteString(res, `[]`); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	document, err := client.Signature.CreateDocumentIdempotent(context.Background(), createReq)
+	assert.NoError(t, err)
+	assert.Equal(t, "someDocumentId", document.DocumentID)
+	assert.Equal(t, 2, posts)
+	assert.Equal(t, 1, lookups)
+	assert.Equal(t, "", createReq.ExternalID)
+}
+
+func TestSignatureService_CreateDocumentIdempotent_ClientError(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/signature/documents", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		res.WriteHeader(http.StatusBadRequest)
+	})
+
+	_, err := client.Signature.CreateDocumentIdempotent(context.Background(), &CreateDocumentRequest{ExternalID: "someExternalId"})
+	assert.Equal(t, &ErrorResponse{StatusCode: http.StatusBadRequest}, err)
+}
+
+func TestSignatureService_CreateDocumentIdempotent_Deadline(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var posts int
+	mux.HandleFunc("/signature/documents", func(res http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			posts++
+			// The document is created, but the response stalls until the client gives up.
+			_, err := io.Copy(ioutil.Discard, req.Body)
+			assert.NoError(t, err)
+			<-req.Context().Done()
+		case http.MethodGet:
+			if _, err := io.WriteString(res, `[{"documentId":"someDocumentId","externalId":"someExternalId"}]`); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	document, err := client.Signature.CreateDocumentIdempotent(ctx, &CreateDocumentRequest{ExternalID: "someExternalId"})
+	assert.NoError(t, err)
+	assert.Equal(t, "someDocumentId", document.DocumentID)
+	assert.Equal(t, 1, posts)
+}
+
+func TestSignatureService_CreateDocumentIdempotent_LookupError(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/signature/documents", func(res http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodPost:
+			res.WriteHeader(http.StatusBadGateway)
+		case http.MethodGet:
+			res.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+
+	_, err := client.Signature.CreateDocumentIdempotent(context.Background(), &CreateDocumentRequest{ExternalID: "someExternalId"})
+	var lookupErr *LookupError
+	assert.True(t, errors.As(err, &lookupErr))
+	assert.Equal(t, &ErrorResponse{StatusCode: http.StatusBadGateway}, lookupErr.Err)
+	assert.Equal(t, &ErrorResponse{StatusCode: http.StatusServiceUnavailable}, lookupErr.LookupErr)
+	assert.EqualError(t, err, "received response with http code: 502, and looking up the document failed: received response with http code: 503")
+}
+
+func TestSignatureService_CreateDocumentIdempotent_Conflict(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		err      string
+	}{
+		{
+			name:     "several documents",
+			existing: `[{"documentId":"first","title":"Contract"},{"documentId":"second","title":"Contract"}]`,
+			err:      "idempotency key conflict: 2 documents have the external ID someExternalId",
+		},
+		{
+			name:     "canceled",
+			existing: `[{"documentId":"someDocumentId","title":"Contract","status":{"documentStatus":"canceled"}}]`,
+			err:      "idempotency key conflict: document someDocumentId with the external ID someExternalId is canceled",
+		},
+		{
+			name:     "other title",
+			existing: `[{"documentId":"someDocumentId","title":"Another contract"}]`,
+			err:      `idempotency key conflict: document someDocumentId has the title "Another contract", not "Contract"`,
+		},
+		{
+			name:     "other signers",
+			existing: `[{"documentId":"someDocumentId","title":"Contract","signers":[{"externalSignerId":"kari"}]}]`,
+			err:      "idempotency key conflict: document someDocumentId has the signers [kari], not [ola]",
+		},
+	}
+
+	for _, test := range tests {
+		client, mux, teardown := setup()
+
+		mux.HandleFunc("/signature/documents", func(res http.ResponseWriter, req *http.Request) {
+			switch req.Method {
+			case http.MethodPost:
+				res.WriteHeader(http.StatusBadGateway)
+			case http.MethodGet:
+				if _, err := io.WriteString(res, test.existing); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+
+		_, err := client.Signature.CreateDocumentIdempotent(context.Background(), &CreateDocumentRequest{
+			ExternalID: "someExternalId",
+			Title:      "Contract",
+			Signers:    []*SignerRequest{{ExternalSignerID: "ola"}},
+		})
+		assert.True(t, errors.Is(err, ErrIdempotencyConflict), test.name)
+		assert.EqualError(t, err, test.err, test.name)
+
+		teardown()
+	}
+}
+
+func TestSignatureService_CreateDocumentIdempotent_NotSent(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/signature/documents", func(res http.ResponseWriter, req *http.Request) {
+		t.Fatalf("unexpected %s request", req.Method)
+	})
+
+	// The request can't be encoded, so it is never sent and there is nothing to look up.
+	_, err := client.Signature.CreateDocumentIdempotent(context.Background(), &CreateDocumentRequest{
+		ExternalID: "someExternalId",
+		Title:      contentPlaceholder,
+		DataToSign: &DataToSign{Content: strings.NewReader("contract")},
+	})
+	assert.EqualError(t, err, "request can't be streamed, the content placeholder is used elsewhere in the request")
+}
+
+func TestIsAmbiguous(t *testing.T) {
+	assert.True(t, isAmbiguous(&ErrorResponse{StatusCode: http.StatusBadGateway}))
+	assert.False(t, isAmbiguous(&ErrorResponse{StatusCode: http.StatusConflict}))
+	assert.True(t, isAmbiguous(&url.Error{Op: "Post", URL: "https://example.com", Err: io.ErrUnexpectedEOF}))
+	assert.True(t, isAmbiguous(context.DeadlineExceeded))
+	assert.False(t, isAmbiguous(errors.New("encoding the request failed")))
+}
@@ -0,0 +1,155 @@
+package signicat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Available identification flows.
+const (
+	IdentificationFlowRedirect = "redirect"
+	IdentificationFlowIframe   = "iframe"
+
+	// Available identification providers.
+	IdentificationProviderNoBankIDNetCentric = "no_bankid_netcentric"
+	IdentificationProviderNoBankIDMobile     = "no_bankid_mobile"
+	IdentificationProviderNoBuypass          = "no_buypass"
+	IdentificationProviderSeBankID           = "se_bankid"
+	IdentificationProviderDkNemID            = "dk_nemid"
+	IdentificationProviderDkMitID            = "dk_mitid"
+	IdentificationProviderFiFTN              = "fi_ftn"
+	IdentificationProviderFiTupas            = "fi_tupas"
+	IdentificationProviderFiMobiilivarmenne  = "fi_mobiilivarmenne"
+
+	// Available identity attributes to include in the session result.
+	IdentificationIncludeName        = "name"
+	IdentificationIncludeDateOfBirth = "date_of_birth"
+	IdentificationIncludePhoneNumber = "phone_number"
+	IdentificationIncludeNin         = "nin"
+	IdentificationIncludeEmail       = "email"
+	IdentificationIncludeAddress     = "address"
+
+	// Available identification session statuses.
+	IdentificationStatusActive  = "active"
+	IdentificationStatusSuccess = "success"
+	IdentificationStatusAborted = "aborted"
+	IdentificationStatusExpired = "expired"
+	IdentificationStatusFailed  = "failed"
+)
+
+// IdentificationService handles communication with the Identification API.
+type IdentificationService service
+
+// CreateSession creates a new identification session. The end user should be sent to the URL in the response, either by redirect
+// or by embedding it in an iframe, depending on the flow.
+func (s *IdentificationService) CreateSession(ctx context.Context, createReq *CreateSessionRequest) (*Session, error) {
+	req, err := s.client.NewRequest(http.MethodPost, "/identification/v2/sessions", createReq)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(Session)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// RetrieveSession retrieves a single identification session. When the session status is success, the identity of the end user is
+// included in the response.
+func (s *IdentificationService) RetrieveSession(ctx context.Context, sessionID string) (*Session, error) {
+	u := fmt.Sprintf("/identification/v2/sessions/%s", sessionID)
+	req, err := s.client.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(Session)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// CreateSessionRequest is the request body used to create an identification session.
+type CreateSessionRequest struct {
+	Flow               string                          `json:"flow"`
+	AllowedProviders   []string                        `json:"allowedProviders,omitempty"`
+	Include            []string                        `json:"include,omitempty"`
+	RedirectSettings   *IdentificationRedirectSettings `json:"redirectSettings,omitempty"`
+	IFrameSettings     *IFrameSettings                 `json:"iFrameSettings,omitempty"`
+	UI                 *UI                             `json:"ui,omitempty"`
+	ExternalReference  string                          `json:"externalReference,omitempty"`
+	ExpiresIn          int32                           `json:"expiresIn,omitempty"`
+	PrefilledInput     *PrefilledInput                 `json:"prefilledInput,omitempty"`
+	ProviderParameters map[string]string               `json:"providerParameters,omitempty"`
+}
+
+// IdentificationRedirectSettings holds the URLs the end user is sent back to when using the redirect flow.
+type IdentificationRedirectSettings struct {
+	SuccessURL string `json:"successUrl"`
+	AbortURL   string `json:"abortUrl"`
+	ErrorURL   string `json:"errorUrl"`
+}
+
+// IFrameSettings holds the settings used when the session is embedded in an iframe.
+type IFrameSettings struct {
+	ParentDomains           []string `json:"parentDomains"`
+	PostMessageTargetOrigin string   `json:"postMessageTargetOrigin,omitempty"`
+}
+
+// PrefilledInput is used to prefill values in the identity providers, eg. the national identity number.
+type PrefilledInput struct {
+	Nin         string `json:"nin,omitempty"`
+	PhoneNumber string `json:"phoneNumber,omitempty"`
+	Email       string `json:"email,omitempty"`
+}
+
+// Session is an identification session.
+type Session struct {
+	ID                string                          `json:"id,omitempty"`
+	URL               string                          `json:"url,omitempty"`
+	Status            string                          `json:"status,omitempty"`
+	Flow              string                          `json:"flow,omitempty"`
+	AllowedProviders  []string                        `json:"allowedProviders,omitempty"`
+	Include           []string                        `json:"include,omitempty"`
+	RedirectSettings  *IdentificationRedirectSettings `json:"redirectSettings,omitempty"`
+	IFrameSettings    *IFrameSettings                 `json:"iFrameSettings,omitempty"`
+	UI                *UI                             `json:"ui,omitempty"`
+	ExternalReference string                          `json:"externalReference,omitempty"`
+	ExpiresAt         *time.Time                      `json:"expiresAt,omitempty"`
+	Identity          *Identity                       `json:"identity,omitempty"`
+	Error             *SessionError                   `json:"error,omitempty"`
+}
+
+// Identity holds the identity attributes of an identified end user. Which attributes are set depends on the provider used and what
+// was requested in Include.
+type Identity struct {
+	ProviderID  string `json:"providerId,omitempty"`
+	FullName    string `json:"fullName,omitempty"`
+	FirstName   string `json:"firstName,omitempty"`
+	MiddleName  string `json:"middleName,omitempty"`
+	LastName    string `json:"lastName,omitempty"`
+	DateOfBirth string `json:"dateOfBirth,omitempty"`
+	Nin         *Nin   `json:"nin,omitempty"`
+	PhoneNumber string `json:"phoneNumber,omitempty"`
+	Email       string `json:"email,omitempty"`
+	Gender      string `json:"gender,omitempty"`
+}
+
+// Nin is a national identity number.
+type Nin struct {
+	Value          string `json:"value,omitempty"`
+	IssuingCountry string `json:"issuingCountry,omitempty"`
+	Type           string `json:"type,omitempty"`
+}
+
+// SessionError describes why an identification session failed.
+type SessionError struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,58 @@
+package signicat
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestIdentificationService_CreateSession(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Equal(t, "/identification/v2/sessions", req.URL.Path)
+
+		var body CreateSessionRequest
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		assert.Equal(t, IdentificationFlowIframe, body.Flow)
+		assert.Equal(t, []string{"example.com"}, body.IFrameSettings.ParentDomains)
+
+		if _, err := io.WriteString(res, `{"id":"someSessionId","url":"https://example.com/session","status":"active"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	session, err := client.Identification.CreateSession(context.Background(), &CreateSessionRequest{
+		Flow:             IdentificationFlowIframe,
+		AllowedProviders: []string{IdentificationProviderNoBankIDNetCentric},
+		IFrameSettings:   &IFrameSettings{ParentDomains: []string{"example.com"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "someSessionId", session.ID)
+	assert.Equal(t, IdentificationStatusActive, session.Status)
+}
+
+func TestIdentificationService_RetrieveSession(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, "/identification/v2/sessions/someSessionId", req.URL.Path)
+		if _, err := io.WriteString(res, `{"id":"someSessionId","status":"success","identity":{"firstName":"Ola","nin":{"value":"01017012345","issuingCountry":"NO"}}}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	session, err := client.Identification.RetrieveSession(context.Background(), "someSessionId")
+	assert.NoError(t, err)
+	assert.Equal(t, IdentificationStatusSuccess, session.Status)
+	assert.Equal(t, "Ola", session.Identity.FirstName)
+	assert.Equal(t, "NO", session.Identity.Nin.IssuingCountry)
+}
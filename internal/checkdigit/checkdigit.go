@@ -0,0 +1,42 @@
+// Package checkdigit holds the check digit algorithms shared by the national identity and organization numbers.
+package checkdigit
+
+// Digits returns the value of each digit in s. Returns false if s is empty or has other characters than digits.
+func Digits(s string) ([]int, bool) {
+	d := make([]int, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return nil, false
+		}
+		d[i] = int(s[i] - '0')
+	}
+
+	return d, len(d) > 0
+}
+
+// WeightedSum returns the sum of the first digits of d multiplied by the weights.
+func WeightedSum(d, weights []int) int {
+	sum := 0
+	for i, w := range weights {
+		sum += d[i] * w
+	}
+
+	return sum
+}
+
+// Luhn reports whether the last digit of d is the Luhn check digit of the others.
+func Luhn(d []int) bool {
+	sum := 0
+	for i := range d {
+		v := d[len(d)-1-i]
+		if i%2 == 1 {
+			v *= 2
+			if v > 9 {
+				v -= 9
+			}
+		}
+		sum += v
+	}
+
+	return sum%10 == 0
+}
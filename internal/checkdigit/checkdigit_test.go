@@ -0,0 +1,37 @@
+package checkdigit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDigits(t *testing.T) {
+	d, ok := Digits("0123456789")
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, d)
+
+	for _, s := range []string{"", "12a4", "12-4", "١٢"} {
+		_, ok := Digits(s)
+		assert.False(t, ok, s)
+	}
+}
+
+func TestWeightedSum(t *testing.T) {
+	assert.Equal(t, 1*3+2*2+3*1, WeightedSum([]int{1, 2, 3, 4}, []int{3, 2, 1}))
+}
+
+func TestLuhn(t *testing.T) {
+	valid := map[string]bool{
+		"8112289874":       true,
+		"5560160680":       true,
+		"4111111111111111": true,
+		"8112289875":       false,
+		"5560160681":       false,
+	}
+
+	for s, want := range valid {
+		d, _ := Digits(s)
+		assert.Equal(t, want, Luhn(d), s)
+	}
+}
@@ -0,0 +1,45 @@
+// Command modelgen generates Go models from an OpenAPI document. It is run with go generate, see signature.go.
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/larwef/signicat/internal/modelgen"
+)
+
+func main() {
+	pkg := flag.String("package", "signicat", "package name of the generated file")
+	specPath := flag.String("spec", "", "path to the OpenAPI document")
+	overlayPath := flag.String("overlay", "", "path to the overlay, optional")
+	out := flag.String("out", "", "path to the generated file")
+	flag.Parse()
+
+	if *specPath == "" || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	spec, err := ioutil.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var overlay []byte
+	if *overlayPath != "" {
+		if overlay, err = ioutil.ReadFile(*overlayPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	src, err := modelgen.Generate(*pkg, *specPath, spec, overlay)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,216 @@
+// Package cms reads CMS (PKCS #7) signed data, as used in native signatures and timestamp tokens, without verifying it.
+package cms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+var (
+	oidSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSigningTime   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+)
+
+// Digest algorithms, keyed by their object identifier.
+var digestAlgorithms = map[string]crypto.Hash{
+	"1.3.14.3.2.26":          crypto.SHA1,
+	"2.16.840.1.101.3.4.2.4": crypto.SHA224,
+	"2.16.840.1.101.3.4.2.1": crypto.SHA256,
+	"2.16.840.1.101.3.4.2.2": crypto.SHA384,
+	"2.16.840.1.101.3.4.2.3": crypto.SHA512,
+}
+
+// DigestAlgorithm returns the hash function of a digest algorithm object identifier, or 0 if it is unknown.
+func DigestAlgorithm(oid asn1.ObjectIdentifier) crypto.Hash {
+	return digestAlgorithms[oid.String()]
+}
+
+// SignedData is CMS signed data.
+type SignedData struct {
+	ContentType asn1.ObjectIdentifier
+	// Content is the encapsulated content, or nil if the signature is detached.
+	Content      []byte
+	Certificates []*x509.Certificate
+	Signers      []*Signer
+}
+
+// Signer is the signer info of a signer.
+type Signer struct {
+	DigestAlgorithm asn1.ObjectIdentifier
+	// MessageDigest is the digest of the content from the signed attributes.
+	MessageDigest []byte
+	// SigningTime is the signing time from the signed attributes, if any.
+	SigningTime *time.Time
+	// Certificate is the certificate of the signer, if it is in the signed data.
+	Certificate *x509.Certificate
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type encapContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+// Parse parses a DER encoded content info holding signed data.
+func Parse(der []byte) (*SignedData, error) {
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(der, &ci); err != nil {
+		return nil, err
+	}
+	if !ci.ContentType.Equal(oidSignedData) {
+		return nil, fmt.Errorf("content type %s is not signed data", ci.ContentType)
+	}
+
+	// The optional fields make the signed data easier to read element by element.
+	fields, err := sequence(ci.Content.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) < 4 {
+		return nil, errors.New("signed data has too few elements")
+	}
+
+	sd := &SignedData{}
+
+	var eci encapContentInfo
+	if _, err := asn1.Unmarshal(fields[2].FullBytes, &eci); err != nil {
+		return nil, err
+	}
+	sd.ContentType = eci.EContentType
+	if len(eci.EContent.Bytes) > 0 {
+		if _, err := asn1.Unmarshal(eci.EContent.Bytes, &sd.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	signerInfos := fields[len(fields)-1]
+	for _, e := range fields[3 : len(fields)-1] {
+		if e.Class == asn1.ClassContextSpecific && e.Tag == 0 {
+			if sd.Certificates, err = x509.ParseCertificates(e.Bytes); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	infos, err := elements(signerInfos.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		signer, err := sd.parseSigner(info.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		sd.Signers = append(sd.Signers, signer)
+	}
+
+	return sd, nil
+}
+
+func (sd *SignedData) parseSigner(der []byte) (*Signer, error) {
+	fields, err := elements(der)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) < 5 {
+		return nil, errors.New("signer info has too few elements")
+	}
+
+	signer := &Signer{}
+
+	var sid issuerAndSerialNumber
+	if fields[1].Class == asn1.ClassUniversal {
+		if _, err := asn1.Unmarshal(fields[1].FullBytes, &sid); err != nil {
+			return nil, err
+		}
+		for _, c := range sd.Certificates {
+			if c.SerialNumber.Cmp(sid.SerialNumber) == 0 && bytes.Equal(c.RawIssuer, sid.Issuer.FullBytes) {
+				signer.Certificate = c
+			}
+		}
+	}
+
+	var digestAlgorithm struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.RawValue `asn1:"optional"`
+	}
+	if _, err := asn1.Unmarshal(fields[2].FullBytes, &digestAlgorithm); err != nil {
+		return nil, err
+	}
+	signer.DigestAlgorithm = digestAlgorithm.Algorithm
+
+	if fields[3].Class != asn1.ClassContextSpecific || fields[3].Tag != 0 {
+		return signer, nil
+	}
+	attributes, err := elements(fields[3].Bytes)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range attributes {
+		var attr attribute
+		if _, err := asn1.Unmarshal(a.FullBytes, &attr); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case attr.Type.Equal(oidMessageDigest):
+			if _, err := asn1.Unmarshal(attr.Values.Bytes, &signer.MessageDigest); err != nil {
+				return nil, err
+			}
+		case attr.Type.Equal(oidSigningTime):
+			var t time.Time
+			if _, err := asn1.Unmarshal(attr.Values.Bytes, &t); err != nil {
+				return nil, err
+			}
+			signer.SigningTime = &t
+		}
+	}
+
+	return signer, nil
+}
+
+// sequence returns the elements of the DER encoded sequence.
+func sequence(der []byte) ([]asn1.RawValue, error) {
+	var seq asn1.RawValue
+	if _, err := asn1.Unmarshal(der, &seq); err != nil {
+		return nil, err
+	}
+	return elements(seq.Bytes)
+}
+
+// elements splits the DER encoded content of a sequence or set into its elements.
+func elements(der []byte) ([]asn1.RawValue, error) {
+	var values []asn1.RawValue
+	for len(der) > 0 {
+		var v asn1.RawValue
+		rest, err := asn1.Unmarshal(der, &v)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		der = rest
+	}
+
+	return values, nil
+}
@@ -0,0 +1,41 @@
+package cms
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/larwef/signicat/internal/testpki"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	leaf, ca := testpki.Chain("Ola Nordmann", "9578-6000-4-123456")
+	signingTime := time.Date(2020, 6, 26, 12, 0, 0, 0, time.UTC)
+
+	sd, err := Parse(testpki.SignedData([]byte("document"), signingTime, leaf, ca))
+	assert.NoError(t, err)
+	assert.Nil(t, sd.Content)
+	assert.Len(t, sd.Certificates, 2)
+	assert.Len(t, sd.Signers, 1)
+
+	signer := sd.Signers[0]
+	digest := sha256.Sum256([]byte("document"))
+	assert.Equal(t, digest[:], signer.MessageDigest)
+	assert.Equal(t, crypto.SHA256, DigestAlgorithm(signer.DigestAlgorithm))
+	assert.Equal(t, signingTime, *signer.SigningTime)
+	assert.Equal(t, leaf, signer.Certificate)
+}
+
+func TestParse_Timestamp(t *testing.T) {
+	sd, err := Parse(testpki.Timestamp(time.Now(), []byte("data")))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, sd.Content)
+	assert.Empty(t, sd.Signers)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse([]byte("not der"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,426 @@
+// Package modelgen generates Go models from the schemas of an OpenAPI document.
+//
+// Object schemas become structs and string schemas with an enum become constants. Property order is kept as in the document, a
+// property is tagged omitempty unless it is required, and descriptions become doc comments. An overlay can rename types and fields,
+// override field types and add fields which only exist on the Go side. Helpers which can't be expressed in the overlay belong in
+// hand-written files next to the generated one.
+package modelgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Spec is the part of an OpenAPI document used to generate models.
+type Spec struct {
+	Components struct {
+		Schemas json.RawMessage `json:"schemas"`
+	} `json:"components"`
+}
+
+// Schema is an OpenAPI schema.
+type Schema struct {
+	Ref                  string          `json:"$ref"`
+	Type                 string          `json:"type"`
+	Format               string          `json:"format"`
+	Description          string          `json:"description"`
+	Required             []string        `json:"required"`
+	Properties           json.RawMessage `json:"properties"`
+	Items                *Schema         `json:"items"`
+	AdditionalProperties *Schema         `json:"additionalProperties"`
+	Enum                 []string        `json:"enum"`
+	EnumVarNames         []string        `json:"x-enum-varnames"`
+	GoName               string          `json:"x-go-name"`
+
+	properties    map[string]*Schema
+	propertyOrder []string
+}
+
+// Overlay holds hand-tuned changes applied on top of the document.
+type Overlay struct {
+	Types map[string]*TypeOverlay `json:"types"`
+}
+
+// TypeOverlay holds the changes to a single schema.
+type TypeOverlay struct {
+	// GoName renames the generated type.
+	GoName string `json:"goName"`
+	// Fields holds changes to the properties, keyed by property name.
+	Fields map[string]*FieldOverlay `json:"fields"`
+	// ExtraFields are added after the generated fields.
+	ExtraFields []*ExtraField `json:"extraFields"`
+}
+
+// FieldOverlay holds the changes to a single property.
+type FieldOverlay struct {
+	GoName  string `json:"goName"`
+	GoType  string `json:"goType"`
+	Comment string `json:"comment"`
+}
+
+// ExtraField is a field which only exists on the Go side.
+type ExtraField struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Tag     string `json:"tag"`
+	Comment string `json:"comment"`
+}
+
+// initialisms are written in upper case in Go names.
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "IP": true, "JSON": true, "PDF": true, "UI": true, "URL": true, "XML": true,
+}
+
+// Generate returns the formatted Go source for the models in spec, with overlay applied. overlay can be nil.
+func Generate(packageName, source string, spec, overlay []byte) ([]byte, error) {
+	var s Spec
+	if err := json.Unmarshal(spec, &s); err != nil {
+		return nil, fmt.Errorf("parsing spec: %v", err)
+	}
+
+	o := &Overlay{}
+	if len(overlay) > 0 {
+		if err := json.Unmarshal(overlay, o); err != nil {
+			return nil, fmt.Errorf("parsing overlay: %v", err)
+		}
+	}
+
+	names, schemas, err := parseSchemas(s.Components.Schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{schemas: schemas, overlay: o, imports: make(map[string]bool)}
+
+	var consts, types bytes.Buffer
+	for _, name := range names {
+		schema := schemas[name]
+		switch {
+		case schema.Type == "string" && len(schema.Enum) > 0:
+			if err := g.writeEnum(&consts, name, schema); err != nil {
+				return nil, err
+			}
+		case schema.Type == "object":
+			if err := g.writeStruct(&types, name, schema); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("schema %s: unsupported type %q", name, schema.Type)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by modelgen from %s. DO NOT EDIT.\n\npackage %s\n\n", source, packageName)
+
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for imp := range g.imports {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+
+		if len(imports) == 1 {
+			fmt.Fprintf(&buf, "import %q\n\n", imports[0])
+		} else {
+			buf.WriteString("import (\n")
+			for _, imp := range imports {
+				fmt.Fprintf(&buf, "\t%q\n", imp)
+			}
+			buf.WriteString(")\n\n")
+		}
+	}
+
+	if consts.Len() > 0 {
+		// The comment of the first group is written above the block, the rest inside it.
+		c := consts.String()
+		if strings.HasPrefix(c, "\n") {
+			c = c[1:]
+		}
+		if i := strings.Index(c, "\n"); i >= 0 && strings.HasPrefix(c, "\t//") {
+			buf.WriteString(strings.TrimPrefix(c[:i+1], "\t"))
+			c = c[i+1:]
+		}
+		fmt.Fprintf(&buf, "const (\n%s)\n\n", c)
+	}
+
+	buf.Write(types.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated source: %v\n%s", err, buf.Bytes())
+	}
+
+	return src, nil
+}
+
+type generator struct {
+	schemas map[string]*Schema
+	overlay *Overlay
+	imports map[string]bool
+}
+
+func (g *generator) writeEnum(w *bytes.Buffer, name string, schema *Schema) error {
+	if len(schema.EnumVarNames) != len(schema.Enum) {
+		return fmt.Errorf("schema %s: x-enum-varnames must have one name per enum value", name)
+	}
+
+	w.WriteString("\n")
+	if schema.Description != "" {
+		writeComment(w, "\t", schema.Description)
+	}
+	for i, value := range schema.Enum {
+		fmt.Fprintf(w, "\t%s = %q\n", schema.EnumVarNames[i], value)
+	}
+
+	return nil
+}
+
+func (g *generator) writeStruct(w *bytes.Buffer, name string, schema *Schema) error {
+	typeName := g.typeName(name)
+	to := g.overlay.Types[name]
+
+	if schema.Description != "" {
+		writeComment(w, "", typeName+" is "+lowerFirst(schema.Description))
+	}
+	fmt.Fprintf(w, "type %s struct {\n", typeName)
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, r := range schema.Required {
+		required[r] = true
+	}
+
+	for _, prop := range schema.propertyOrder {
+		ps := schema.properties[prop]
+
+		var fo *FieldOverlay
+		if to != nil {
+			fo = to.Fields[prop]
+		}
+
+		fieldName := goName(prop)
+		if ps.GoName != "" {
+			fieldName = ps.GoName
+		}
+		if fo != nil && fo.GoName != "" {
+			fieldName = fo.GoName
+		}
+
+		fieldType, err := g.goType(ps)
+		if err != nil {
+			return fmt.Errorf("schema %s property %s: %v", name, prop, err)
+		}
+		if fo != nil && fo.GoType != "" {
+			fieldType = fo.GoType
+		}
+		g.addImports(fieldType)
+
+		comment := ps.Description
+		if fo != nil && fo.Comment != "" {
+			comment = fo.Comment
+		}
+		if comment != "" {
+			writeComment(w, "\t", comment)
+		}
+
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(w, "\t%s %s `json:%q`\n", fieldName, fieldType, tag)
+	}
+
+	if to != nil {
+		for _, f := range to.ExtraFields {
+			if f.Comment != "" {
+				writeComment(w, "\t", f.Comment)
+			}
+			g.addImports(f.Type)
+			if f.Tag != "" {
+				fmt.Fprintf(w, "\t%s %s `%s`\n", f.Name, f.Type, f.Tag)
+			} else {
+				fmt.Fprintf(w, "\t%s %s\n", f.Name, f.Type)
+			}
+		}
+	}
+
+	w.WriteString("}\n\n")
+
+	return nil
+}
+
+func (g *generator) goType(s *Schema) (string, error) {
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		ref, ok := g.schemas[name]
+		if !ok {
+			return "", fmt.Errorf("unknown reference %q", s.Ref)
+		}
+		if ref.Type == "object" {
+			return "*" + g.typeName(name), nil
+		}
+		return g.goType(ref)
+	}
+
+	switch s.Type {
+	case "string":
+		if s.Format == "date-time" {
+			return "*time.Time", nil
+		}
+		return "string", nil
+	case "boolean":
+		return "bool", nil
+	case "integer":
+		if s.Format == "int64" {
+			return "int64", nil
+		}
+		return "int32", nil
+	case "number":
+		return "float64", nil
+	case "array":
+		if s.Items == nil {
+			return "", fmt.Errorf("array without items")
+		}
+		item, err := g.goType(s.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + item, nil
+	case "object":
+		if s.AdditionalProperties == nil {
+			return "map[string]interface{}", nil
+		}
+		value, err := g.goType(s.AdditionalProperties)
+		if err != nil {
+			return "", err
+		}
+		return "map[string]" + value, nil
+	}
+
+	return "", fmt.Errorf("unsupported type %q", s.Type)
+}
+
+func (g *generator) typeName(name string) string {
+	if to := g.overlay.Types[name]; to != nil && to.GoName != "" {
+		return to.GoName
+	}
+	if s := g.schemas[name]; s != nil && s.GoName != "" {
+		return s.GoName
+	}
+
+	return name
+}
+
+func (g *generator) addImports(goType string) {
+	for prefix, imp := range map[string]string{"time.": "time", "json.": "encoding/json", "io.": "io"} {
+		if strings.Contains(goType, prefix) {
+			g.imports[imp] = true
+		}
+	}
+}
+
+// parseSchemas parses the schemas object, keeping the order of the schemas and their properties.
+func parseSchemas(raw json.RawMessage) ([]string, map[string]*Schema, error) {
+	names, err := objectKeys(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing schemas: %v", err)
+	}
+
+	schemas := make(map[string]*Schema)
+	if err := json.Unmarshal(raw, &schemas); err != nil {
+		return nil, nil, fmt.Errorf("parsing schemas: %v", err)
+	}
+
+	for _, name := range names {
+		if err := parseProperties(schemas[name]); err != nil {
+			return nil, nil, fmt.Errorf("schema %s: %v", name, err)
+		}
+	}
+
+	return names, schemas, nil
+}
+
+func parseProperties(s *Schema) error {
+	if len(s.Properties) == 0 {
+		return nil
+	}
+
+	order, err := objectKeys(s.Properties)
+	if err != nil {
+		return err
+	}
+	s.propertyOrder = order
+
+	return json.Unmarshal(s.Properties, &s.properties)
+}
+
+// objectKeys returns the keys of a JSON object in the order they appear.
+func objectKeys(raw json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, fmt.Errorf("expected object")
+	}
+
+	var keys []string
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, t.(string))
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+// goName converts a camel case JSON name to an exported Go name, eg. signUrlExpires to SignURLExpires.
+func goName(name string) string {
+	var words []string
+	start := 0
+	runes := []rune(name)
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsUpper(runes[i]) && !unicode.IsUpper(runes[i-1]) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	words = append(words, string(runes[start:]))
+
+	var b strings.Builder
+	for _, word := range words {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	r := []rune(s)
+	if len(r) > 1 && unicode.IsUpper(r[1]) {
+		return s
+	}
+	r[0] = unicode.ToLower(r[0])
+
+	return string(r)
+}
+
+func writeComment(w *bytes.Buffer, indent, comment string) {
+	for _, line := range strings.Split(strings.TrimSpace(comment), "\n") {
+		fmt.Fprintf(w, "%s// %s\n", indent, line)
+	}
+}
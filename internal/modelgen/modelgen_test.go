@@ -0,0 +1,91 @@
+package modelgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"id":                      "ID",
+		"documentId":              "DocumentID",
+		"signUrlExpires":          "SignURLExpires",
+		"convertToPdf":            "ConvertToPDF",
+		"clientIp":                "ClientIP",
+		"ui":                      "UI",
+		"base64Content":           "Base64Content",
+		"signatureMethodUniqueId": "SignatureMethodUniqueID",
+		"sms":                     "Sms",
+	}
+
+	for name, expected := range tests {
+		assert.Equal(t, expected, goName(name), name)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	spec := `{
+  "components": {
+    "schemas": {
+      "Color": {"type": "string", "description": "Available colors.", "enum": ["red", "blue"], "x-enum-varnames": ["ColorRed", "ColorBlue"]},
+      "Size": {"type": "string", "description": "Available sizes.", "enum": ["s"], "x-enum-varnames": ["SizeSmall"]},
+      "Shirt": {
+        "type": "object",
+        "description": "A shirt.",
+        "required": ["color"],
+        "properties": {
+          "color": {"$ref": "#/components/schemas/Color"},
+          "shirtId": {"type": "string", "description": "Unique ID of the shirt."},
+          "made": {"type": "string", "format": "date-time"},
+          "sizes": {"type": "array", "items": {"$ref": "#/components/schemas/Size"}},
+          "owner": {"$ref": "#/components/schemas/Owner"},
+          "tags": {"type": "object", "additionalProperties": {"type": "string"}}
+        }
+      },
+      "Owner": {"type": "object", "properties": {"name": {"type": "string"}, "age": {"type": "integer", "format": "int32"}}}
+    }
+  }
+}`
+	overlay := `{"types": {"Owner": {"goName": "Person", "fields": {"age": {"goName": "Years"}}, "extraFields": [{"name": "Raw", "type": "json.RawMessage", "tag": "json:\"-\"", "comment": "Raw is the response."}]}}}`
+
+	expected := "// Code generated by modelgen from test.json. DO NOT EDIT.\n" + `
+package test
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Available colors.
+const (
+	ColorRed  = "red"
+	ColorBlue = "blue"
+
+	// Available sizes.
+	SizeSmall = "s"
+)
+
+// Shirt is a shirt.
+type Shirt struct {
+	Color string ` + "`json:\"color\"`" + `
+	// Unique ID of the shirt.
+	ShirtID string            ` + "`json:\"shirtId,omitempty\"`" + `
+	Made    *time.Time        ` + "`json:\"made,omitempty\"`" + `
+	Sizes   []string          ` + "`json:\"sizes,omitempty\"`" + `
+	Owner   *Person           ` + "`json:\"owner,omitempty\"`" + `
+	Tags    map[string]string ` + "`json:\"tags,omitempty\"`" + `
+}
+
+type Person struct {
+	Name  string ` + "`json:\"name,omitempty\"`" + `
+	Years int32  ` + "`json:\"age,omitempty\"`" + `
+	// Raw is the response.
+	Raw json.RawMessage ` + "`json:\"-\"`" + `
+}
+`
+
+	src, err := Generate("test", "test.json", []byte(spec), []byte(overlay))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(src))
+}
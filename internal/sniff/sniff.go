@@ -0,0 +1,77 @@
+// Package sniff detects the content type of the files Signicat accepts for signing, from their first bytes.
+package sniff
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Detected content types.
+const (
+	PDF     = "application/pdf"
+	DOCX    = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	XML     = "application/xml"
+	Text    = "text/plain; charset=utf-8"
+	Unknown = "application/octet-stream"
+)
+
+// Len is the number of bytes ContentType looks at.
+const Len = 4096
+
+var (
+	pdfMagic = []byte("%PDF-")
+	zipMagic = []byte("PK\x03\x04")
+	utf8BOM  = []byte("\xef\xbb\xbf")
+)
+
+// ContentType returns the content type of a file from its first bytes. The file name is only used to tell DOCX from other zip
+// files when the first bytes don't show it.
+func ContentType(head []byte, fileName string) string {
+	if len(head) > Len {
+		head = head[:Len]
+	}
+
+	switch {
+	case bytes.HasPrefix(head, pdfMagic):
+		return PDF
+	case bytes.HasPrefix(head, zipMagic):
+		// A DOCX is a zip file where the main document is stored under word/.
+		if bytes.Contains(head, []byte("word/")) || strings.EqualFold(filepath.Ext(fileName), ".docx") {
+			return DOCX
+		}
+		return Unknown
+	}
+
+	text := bytes.TrimPrefix(head, utf8BOM)
+	if bytes.HasPrefix(bytes.TrimLeft(text, " \t\r\n"), []byte("<?xml")) {
+		return XML
+	}
+	if isText(text) {
+		return Text
+	}
+
+	return Unknown
+}
+
+// isText reports whether b is UTF-8 without control characters other than whitespace. The last rune may be cut off.
+func isText(b []byte) bool {
+	if len(b) == 0 {
+		return false
+	}
+
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r == utf8.RuneError && size <= 1 {
+			// A multi-byte rune cut off at the end is fine.
+			return len(b) < utf8.UTFMax && !utf8.FullRune(b)
+		}
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' && r != '\f' {
+			return false
+		}
+		b = b[size:]
+	}
+
+	return true
+}
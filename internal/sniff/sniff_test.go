@@ -0,0 +1,31 @@
+package sniff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentType(t *testing.T) {
+	tests := []struct {
+		name     string
+		head     string
+		fileName string
+		expected string
+	}{
+		{name: "pdf", head: "%PDF-1.7\n%\xe2\xe3\xcf\xd3", expected: PDF},
+		{name: "docx", head: "PK\x03\x04\x14\x00\x06\x00[Content_Types].xml PK\x03\x04 word/document.xml", expected: DOCX},
+		{name: "docx by name", head: "PK\x03\x04\x14\x00\x06\x00", fileName: "contract.DOCX", expected: DOCX},
+		{name: "zip", head: "PK\x03\x04\x14\x00\x06\x00", fileName: "contract.zip", expected: Unknown},
+		{name: "xml", head: "<?xml version=\"1.0\"?><contract/>", expected: XML},
+		{name: "xml with bom", head: "\xef\xbb\xbf\n<?xml version=\"1.0\"?>", expected: XML},
+		{name: "text", head: "Contract\r\n\tSigned by Ola Nordmann, Tromsø", expected: Text},
+		{name: "text cut in rune", head: "Tromsø"[:6], expected: Text},
+		{name: "binary", head: "\x00\x01\x02\x03", expected: Unknown},
+		{name: "empty", head: "", expected: Unknown},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, ContentType([]byte(test.head), test.fileName), test.name)
+	}
+}
@@ -0,0 +1,290 @@
+// Package testpki builds certificates, timestamps, CMS signed data and XML signatures for tests. The signatures have the structure
+// of real ones, but the signature values are not valid.
+package testpki
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Chain returns a leaf certificate with the given subject, issued by a self-signed CA.
+func Chain(commonName, serialNumber string) (leaf, ca *x509.Certificate) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:              time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		panic(err)
+	}
+	ca, err = x509.ParseCertificate(caDER)
+	if err != nil {
+		panic(err)
+	}
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: commonName, SerialNumber: serialNumber},
+		NotBefore:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, ca, &leafKey.PublicKey, caKey)
+	if err != nil {
+		panic(err)
+	}
+	leaf, err = x509.ParseCertificate(leafDER)
+	if err != nil {
+		panic(err)
+	}
+
+	return leaf, ca
+}
+
+// Timestamp returns an RFC 3161 timestamp token for the SHA-256 hash of data. The token isn't signed.
+func Timestamp(genTime time.Time, data []byte) []byte {
+	hashed := sha256.Sum256(data)
+
+	tstInfo, err := asn1.Marshal(struct {
+		Version        int
+		Policy         asn1.ObjectIdentifier
+		MessageImprint struct {
+			HashAlgorithm pkix.AlgorithmIdentifier
+			HashedMessage []byte
+		}
+		SerialNumber *big.Int
+		GenTime      time.Time `asn1:"generalized"`
+	}{
+		Version: 1,
+		Policy:  asn1.ObjectIdentifier{1, 2, 3},
+		MessageImprint: struct {
+			HashAlgorithm pkix.AlgorithmIdentifier
+			HashedMessage []byte
+		}{
+			HashAlgorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}},
+			HashedMessage: hashed[:],
+		},
+		SerialNumber: big.NewInt(42),
+		GenTime:      genTime.UTC(),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	eContent, err := asn1.Marshal(tstInfo)
+	if err != nil {
+		panic(err)
+	}
+	signedData, err := asn1.Marshal(struct {
+		Version          int
+		DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+		EncapContentInfo struct {
+			EContentType asn1.ObjectIdentifier
+			EContent     asn1.RawValue
+		}
+		SignerInfos []asn1.RawValue `asn1:"set"`
+	}{
+		Version: 3,
+		EncapContentInfo: struct {
+			EContentType asn1.ObjectIdentifier
+			EContent     asn1.RawValue
+		}{
+			EContentType: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4},
+			EContent:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: eContent},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	token, err := asn1.Marshal(struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue
+	}{
+		ContentType: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2},
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return token
+}
+
+// SignedData returns detached CMS signed data over the SHA-256 digest of content, signed by the first of the certificates. All the
+// certificates are included.
+func SignedData(content []byte, signingTime time.Time, certs ...*x509.Certificate) []byte {
+	digest := sha256.Sum256(content)
+	sha256Algorithm := pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}}
+
+	attribute := func(oid asn1.ObjectIdentifier, value interface{}) []byte {
+		v, err := asn1.Marshal(value)
+		if err != nil {
+			panic(err)
+		}
+		b, err := asn1.Marshal(struct {
+			Type   asn1.ObjectIdentifier
+			Values asn1.RawValue
+		}{Type: oid, Values: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: v}})
+		if err != nil {
+			panic(err)
+		}
+		return b
+	}
+	var attributes []byte
+	attributes = append(attributes, attribute(asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}, digest[:])...)
+	attributes = append(attributes, attribute(asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}, signingTime.UTC())...)
+
+	var rawCerts []byte
+	for _, c := range certs {
+		rawCerts = append(rawCerts, c.Raw...)
+	}
+
+	signerInfo, err := asn1.Marshal(struct {
+		Version int
+		SID     struct {
+			Issuer       asn1.RawValue
+			SerialNumber *big.Int
+		}
+		DigestAlgorithm    pkix.AlgorithmIdentifier
+		SignedAttrs        asn1.RawValue
+		SignatureAlgorithm pkix.AlgorithmIdentifier
+		Signature          []byte
+	}{
+		Version: 1,
+		SID: struct {
+			Issuer       asn1.RawValue
+			SerialNumber *big.Int
+		}{Issuer: asn1.RawValue{FullBytes: certs[0].RawIssuer}, SerialNumber: certs[0].SerialNumber},
+		DigestAlgorithm:    sha256Algorithm,
+		SignedAttrs:        asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attributes},
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}},
+		Signature:          []byte("signature"),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	signedData, err := asn1.Marshal(struct {
+		Version          int
+		DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+		EncapContentInfo struct {
+			EContentType asn1.ObjectIdentifier
+		}
+		Certificates asn1.RawValue
+		SignerInfos  asn1.RawValue
+	}{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{sha256Algorithm},
+		EncapContentInfo: struct {
+			EContentType asn1.ObjectIdentifier
+		}{EContentType: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}},
+		Certificates: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: rawCerts},
+		SignerInfos:  asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: signerInfo},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	contentInfo, err := asn1.Marshal(struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue
+	}{
+		ContentType: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2},
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return contentInfo
+}
+
+// SignatureOptions describes an XML signature.
+type SignatureOptions struct {
+	ID string
+	// Document is the signed data, which the SHA-256 digest of the data reference is computed from.
+	Document    []byte
+	DocumentURI string
+	// Certificates are put in KeyInfo, in the given order.
+	Certificates []*x509.Certificate
+	// SigningTime and Timestamp are added as XAdES properties if set.
+	SigningTime *time.Time
+	Timestamp   *time.Time
+}
+
+// Signature returns a ds:Signature element. The namespace prefix ds is declared on the element.
+func Signature(opts *SignatureOptions) string {
+	digest := sha256.Sum256(opts.Document)
+
+	var certs strings.Builder
+	for _, c := range opts.Certificates {
+		fmt.Fprintf(&certs, "<ds:X509Certificate>%s</ds:X509Certificate>", wrap(base64.StdEncoding.EncodeToString(c.Raw)))
+	}
+
+	var signed, unsigned string
+	if opts.SigningTime != nil {
+		signed = fmt.Sprintf(`<xades:SignedProperties Id="%[1]s-props"><xades:SignedSignatureProperties>`+
+			`<xades:SigningTime>%[2]s</xades:SigningTime></xades:SignedSignatureProperties></xades:SignedProperties>`,
+			opts.ID, opts.SigningTime.Format(time.RFC3339))
+	}
+	if opts.Timestamp != nil {
+		token := Timestamp(*opts.Timestamp, []byte(opts.ID))
+		unsigned = fmt.Sprintf(`<xades:UnsignedProperties><xades:UnsignedSignatureProperties><xades:SignatureTimeStamp>`+
+			`<xades:EncapsulatedTimeStamp>%s</xades:EncapsulatedTimeStamp></xades:SignatureTimeStamp>`+
+			`</xades:UnsignedSignatureProperties></xades:UnsignedProperties>`, base64.StdEncoding.EncodeToString(token))
+	}
+
+	return fmt.Sprintf(`<ds:Signature xmlns:ds="http://www.w3.org/2000/09/xmldsig#" Id="%[1]s">
+  <ds:SignedInfo>
+    <ds:CanonicalizationMethod Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"/>
+    <ds:SignatureMethod Algorithm="http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha256"/>
+    <ds:Reference URI="%[2]s">
+      <ds:DigestMethod Algorithm="http://www.w3.org/2001/04/xmlenc#sha256"/>
+      <ds:DigestValue>%[3]s</ds:DigestValue>
+    </ds:Reference>
+    <ds:Reference URI="#%[1]s-props" Type="http://uri.etsi.org/01903#SignedProperties">
+      <ds:DigestMethod Algorithm="http://www.w3.org/2001/04/xmlenc#sha256"/>
+      <ds:DigestValue>AAAA</ds:DigestValue>
+    </ds:Reference>
+  </ds:SignedInfo>
+  <ds:SignatureValue>%[4]s</ds:SignatureValue>
+  <ds:KeyInfo><ds:X509Data>%[5]s</ds:X509Data></ds:KeyInfo>
+  <ds:Object><xades:QualifyingProperties xmlns:xades="http://uri.etsi.org/01903/v1.3.2#" Target="#%[1]s">%[6]s%[7]s</xades:QualifyingProperties></ds:Object>
+</ds:Signature>`, opts.ID, opts.DocumentURI, base64.StdEncoding.EncodeToString(digest[:]),
+		base64.StdEncoding.EncodeToString([]byte("signature of "+opts.ID)), certs.String(), signed, unsigned)
+}
+
+// wrap breaks base64 into lines of 64 characters, like most XML signers do.
+func wrap(s string) string {
+	var b strings.Builder
+	for len(s) > 64 {
+		b.WriteString(s[:64] + "\n")
+		s = s[64:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
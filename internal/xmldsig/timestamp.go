@@ -0,0 +1,59 @@
+package xmldsig
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/larwef/signicat/internal/cms"
+)
+
+var oidTSTInfo = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+
+// Timestamp is an RFC 3161 timestamp token. The signature of the timestamp authority is not verified.
+type Timestamp struct {
+	Time time.Time
+	// HashAlgorithm and HashedMessage are the message imprint, ie. the hash of the data which was timestamped.
+	HashAlgorithm asn1.ObjectIdentifier
+	HashedMessage []byte
+	SerialNumber  *big.Int
+	// Token is the DER encoded timestamp token.
+	Token []byte
+}
+
+type tstInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint struct {
+		HashAlgorithm pkix.AlgorithmIdentifier
+		HashedMessage []byte
+	}
+	SerialNumber *big.Int
+	GenTime      time.Time `asn1:"generalized"`
+}
+
+// ParseTimestamp parses a DER encoded RFC 3161 timestamp token.
+func ParseTimestamp(token []byte) (*Timestamp, error) {
+	sd, err := cms.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if !sd.ContentType.Equal(oidTSTInfo) {
+		return nil, errors.New("signed data is not a timestamp")
+	}
+
+	var info tstInfo
+	if _, err := asn1.Unmarshal(sd.Content, &info); err != nil {
+		return nil, err
+	}
+
+	return &Timestamp{
+		Time:          info.GenTime,
+		HashAlgorithm: info.MessageImprint.HashAlgorithm.Algorithm,
+		HashedMessage: info.MessageImprint.HashedMessage,
+		SerialNumber:  info.SerialNumber,
+		Token:         token,
+	}, nil
+}
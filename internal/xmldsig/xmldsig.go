@@ -0,0 +1,274 @@
+// Package xmldsig reads XML signatures (XMLDSig), including the XAdES properties, without verifying them.
+package xmldsig
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/sha1" // Register the digest methods.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Namespace is the XMLDSig namespace.
+const Namespace = "http://www.w3.org/2000/09/xmldsig#"
+
+// signedPropertiesType is the reference type of the XAdES signed properties.
+const signedPropertiesType = "http://uri.etsi.org/01903#SignedProperties"
+
+// Signature is an XML signature.
+type Signature struct {
+	ID              string
+	SignatureMethod string
+	References      []*Reference
+	SignatureValue  []byte
+	// Certificates has the certificate of the signer first, followed by the rest of the chain found in the signature.
+	Certificates []*x509.Certificate
+	// SigningTime is the XAdES signing time claimed by the signer, if any.
+	SigningTime *time.Time
+	// Timestamps are the XAdES signature timestamps.
+	Timestamps []*Timestamp
+}
+
+// Reference is a reference to signed data.
+type Reference struct {
+	URI          string
+	Type         string
+	DigestMethod string
+	DigestValue  []byte
+}
+
+// DataReferences returns the references to the signed data, ie. the references except the one to the XAdES signed properties.
+func (s *Signature) DataReferences() []*Reference {
+	var refs []*Reference
+	for _, r := range s.References {
+		if r.Type != signedPropertiesType {
+			refs = append(refs, r)
+		}
+	}
+
+	return refs
+}
+
+// Certificate returns the certificate of the signer, or nil if the signature has none.
+func (s *Signature) Certificate() *x509.Certificate {
+	if len(s.Certificates) == 0 {
+		return nil
+	}
+	return s.Certificates[0]
+}
+
+type signatureXML struct {
+	ID         string `xml:"Id,attr"`
+	SignedInfo struct {
+		SignatureMethod algorithmXML `xml:"SignatureMethod"`
+		References      []struct {
+			URI          string       `xml:"URI,attr"`
+			Type         string       `xml:"Type,attr"`
+			DigestMethod algorithmXML `xml:"DigestMethod"`
+			DigestValue  string       `xml:"DigestValue"`
+		} `xml:"Reference"`
+	} `xml:"SignedInfo"`
+	SignatureValue string   `xml:"SignatureValue"`
+	Certificates   []string `xml:"KeyInfo>X509Data>X509Certificate"`
+	Objects        []struct {
+		SigningTime       string   `xml:"QualifyingProperties>SignedProperties>SignedSignatureProperties>SigningTime"`
+		Timestamps        []string `xml:"QualifyingProperties>UnsignedProperties>UnsignedSignatureProperties>SignatureTimeStamp>EncapsulatedTimeStamp"`
+		CertificateValues []string `xml:"QualifyingProperties>UnsignedProperties>UnsignedSignatureProperties>CertificateValues>EncapsulatedX509Certificate"`
+	} `xml:"Object"`
+}
+
+type algorithmXML struct {
+	Algorithm string `xml:"Algorithm,attr"`
+}
+
+// Parse returns every XML signature in data, in document order. Signatures inside other signatures are not returned.
+func Parse(data []byte) ([]*Signature, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	var signatures []*Signature
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return signatures, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Space != Namespace || start.Name.Local != "Signature" {
+			continue
+		}
+
+		var sx signatureXML
+		if err := dec.DecodeElement(&sx, &start); err != nil {
+			return nil, err
+		}
+		signature, err := sx.signature()
+		if err != nil {
+			return nil, fmt.Errorf("signature %s: %v", sx.ID, err)
+		}
+		signatures = append(signatures, signature)
+	}
+}
+
+func (sx *signatureXML) signature() (*Signature, error) {
+	s := &Signature{
+		ID:              sx.ID,
+		SignatureMethod: sx.SignedInfo.SignatureMethod.Algorithm,
+	}
+
+	var err error
+	if s.SignatureValue, err = decodeBase64(sx.SignatureValue); err != nil {
+		return nil, fmt.Errorf("signature value: %v", err)
+	}
+
+	for _, r := range sx.SignedInfo.References {
+		digest, err := decodeBase64(r.DigestValue)
+		if err != nil {
+			return nil, fmt.Errorf("digest of reference %q: %v", r.URI, err)
+		}
+		s.References = append(s.References, &Reference{
+			URI:          r.URI,
+			Type:         r.Type,
+			DigestMethod: r.DigestMethod.Algorithm,
+			DigestValue:  digest,
+		})
+	}
+
+	encodedCerts := sx.Certificates
+	for _, o := range sx.Objects {
+		encodedCerts = append(encodedCerts, o.CertificateValues...)
+
+		if o.SigningTime != "" && s.SigningTime == nil {
+			t, err := time.Parse(time.RFC3339, strings.TrimSpace(o.SigningTime))
+			if err != nil {
+				return nil, fmt.Errorf("signing time: %v", err)
+			}
+			s.SigningTime = &t
+		}
+
+		for _, encoded := range o.Timestamps {
+			token, err := decodeBase64(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("timestamp: %v", err)
+			}
+			ts, err := ParseTimestamp(token)
+			if err != nil {
+				return nil, fmt.Errorf("timestamp: %v", err)
+			}
+			s.Timestamps = append(s.Timestamps, ts)
+		}
+	}
+
+	var certs []*x509.Certificate
+	for _, encoded := range encodedCerts {
+		der, err := decodeBase64(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("certificate: %v", err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("certificate: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	s.Certificates = OrderChain(certs)
+
+	return s, nil
+}
+
+// OrderChain orders certificates from the leaf to the root. The leaf is the certificate which didn't issue any of the others.
+// Duplicates are removed, and certificates which aren't part of the chain are put last.
+func OrderChain(certs []*x509.Certificate) []*x509.Certificate {
+	var unique []*x509.Certificate
+	for _, c := range certs {
+		duplicate := false
+		for _, u := range unique {
+			duplicate = duplicate || c.Equal(u)
+		}
+		if !duplicate {
+			unique = append(unique, c)
+		}
+	}
+	if len(unique) < 2 {
+		return unique
+	}
+
+	issued := func(issuer, c *x509.Certificate) bool {
+		return issuer != c && bytes.Equal(c.RawIssuer, issuer.RawSubject)
+	}
+
+	var leaf *x509.Certificate
+	for _, c := range unique {
+		isIssuer := false
+		for _, other := range unique {
+			isIssuer = isIssuer || issued(c, other)
+		}
+		if !isIssuer {
+			leaf = c
+			break
+		}
+	}
+	if leaf == nil {
+		return unique
+	}
+
+	chain := []*x509.Certificate{leaf}
+	used := map[*x509.Certificate]bool{leaf: true}
+	for current := leaf; ; {
+		var next *x509.Certificate
+		for _, c := range unique {
+			if !used[c] && issued(c, current) {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		chain = append(chain, next)
+		used[next] = true
+		current = next
+	}
+
+	for _, c := range unique {
+		if !used[c] {
+			chain = append(chain, c)
+		}
+	}
+
+	return chain
+}
+
+// Digest methods, keyed by their XMLDSig algorithm URI.
+var digestMethods = map[string]crypto.Hash{
+	"http://www.w3.org/2000/09/xmldsig#sha1":        crypto.SHA1,
+	"http://www.w3.org/2001/04/xmldsig-more#sha224": crypto.SHA224,
+	"http://www.w3.org/2001/04/xmlenc#sha256":       crypto.SHA256,
+	"http://www.w3.org/2001/04/xmldsig-more#sha384": crypto.SHA384,
+	"http://www.w3.org/2001/04/xmlenc#sha512":       crypto.SHA512,
+}
+
+// DigestMethod returns the hash function of an XMLDSig digest method URI. Returns 0 and false if the method is unknown, or the
+// hash function isn't linked into the binary.
+func DigestMethod(uri string) (crypto.Hash, bool) {
+	h, ok := digestMethods[uri]
+	if !ok || !h.Available() {
+		return 0, false
+	}
+
+	return h, true
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	// Base64 in XML is often wrapped over several lines.
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+}
@@ -0,0 +1,78 @@
+package xmldsig
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/larwef/signicat/internal/testpki"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	leaf, ca := testpki.Chain("Ola Nordmann", "9578-6000-4-123456")
+	signed := time.Date(2020, 6, 26, 12, 0, 0, 0, time.UTC)
+	stamped := signed.Add(time.Second)
+
+	signature := testpki.Signature(&testpki.SignatureOptions{
+		ID:          "sig-1",
+		Document:    []byte("document"),
+		DocumentURI: "document.pdf",
+		// The CA first, to check that the chain is ordered.
+		Certificates: []*x509.Certificate{ca, leaf},
+		SigningTime:  &signed,
+		Timestamp:    &stamped,
+	})
+	data := []byte(`<?xml version="1.0"?><container><ds:Signature xmlns:ds="urn:other">ignored</ds:Signature>` + signature + `</container>`)
+
+	signatures, err := Parse(data)
+	assert.NoError(t, err)
+	assert.Len(t, signatures, 1)
+
+	s := signatures[0]
+	assert.Equal(t, "sig-1", s.ID)
+	assert.Equal(t, "http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha256", s.SignatureMethod)
+	assert.Equal(t, []byte("signature of sig-1"), s.SignatureValue)
+	assert.Equal(t, leaf, s.Certificate())
+	assert.Equal(t, []*x509.Certificate{leaf, ca}, s.Certificates)
+	assert.Equal(t, signed, *s.SigningTime)
+	assert.Len(t, s.Timestamps, 1)
+	assert.Equal(t, stamped, s.Timestamps[0].Time)
+	assert.Equal(t, int64(42), s.Timestamps[0].SerialNumber.Int64())
+
+	refs := s.DataReferences()
+	assert.Len(t, refs, 1)
+	digest := sha256.Sum256([]byte("document"))
+	assert.Equal(t, "document.pdf", refs[0].URI)
+	assert.Equal(t, digest[:], refs[0].DigestValue)
+
+	h, ok := DigestMethod(refs[0].DigestMethod)
+	assert.True(t, ok)
+	assert.Equal(t, 32, h.Size())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse([]byte(`<ds:Signature xmlns:ds="http://www.w3.org/2000/09/xmldsig#"><ds:SignatureValue>!</ds:SignatureValue></ds:Signature>`))
+	assert.Error(t, err)
+
+	_, err = Parse([]byte(`<unclosed>`))
+	assert.Error(t, err)
+
+	signatures, err := Parse([]byte(`<empty/>`))
+	assert.NoError(t, err)
+	assert.Empty(t, signatures)
+}
+
+func TestParseTimestamp(t *testing.T) {
+	genTime := time.Date(2020, 6, 26, 12, 0, 0, 0, time.UTC)
+	ts, err := ParseTimestamp(testpki.Timestamp(genTime, []byte("data")))
+	assert.NoError(t, err)
+	assert.Equal(t, genTime, ts.Time)
+
+	hashed := sha256.Sum256([]byte("data"))
+	assert.Equal(t, hashed[:], ts.HashedMessage)
+
+	_, err = ParseTimestamp([]byte("not a timestamp"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,191 @@
+package signicat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// documentTransitions holds the statuses a document can move to from each status. Signed, canceled and expired are terminal.
+var documentTransitions = map[string][]string{
+	DocumentStatusUnsigned: {
+		DocumentStatusWaitingForAttachments,
+		DocumentStatusPartialSigned,
+		DocumentStatusSigned,
+		DocumentStatusCanceled,
+		DocumentStatusExpired,
+	},
+	DocumentStatusWaitingForAttachments: {
+		DocumentStatusUnsigned,
+		DocumentStatusPartialSigned,
+		DocumentStatusSigned,
+		DocumentStatusCanceled,
+		DocumentStatusExpired,
+	},
+	DocumentStatusPartialSigned: {
+		DocumentStatusWaitingForAttachments,
+		DocumentStatusSigned,
+		DocumentStatusCanceled,
+		DocumentStatusExpired,
+	},
+	DocumentStatusSigned:   {},
+	DocumentStatusCanceled: {},
+	DocumentStatusExpired:  {},
+}
+
+// ValidateTransition returns an error if a document can't move from one status to another. Staying in the same status is valid.
+func ValidateTransition(from, to string) error {
+	next, ok := documentTransitions[from]
+	if !ok {
+		return fmt.Errorf("unknown document status %q", from)
+	}
+	if _, ok := documentTransitions[to]; !ok {
+		return fmt.Errorf("unknown document status %q", to)
+	}
+	if from == to {
+		return nil
+	}
+
+	for _, status := range next {
+		if status == to {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid document status transition from %s to %s", from, to)
+}
+
+// IsTerminal reports whether the document has reached a status it can't leave.
+func (s *Status) IsTerminal() bool {
+	switch s.DocumentStatus {
+	case DocumentStatusSigned, DocumentStatusCanceled, DocumentStatusExpired:
+		return true
+	}
+	return false
+}
+
+// CanCancel reports whether the document can be canceled. Unknown statuses are left for the API to decide.
+func (s *Status) CanCancel() bool {
+	return !s.IsTerminal()
+}
+
+// CanUpdate reports whether the document can be updated. Unknown statuses are left for the API to decide.
+func (s *Status) CanUpdate() bool {
+	return !s.IsTerminal()
+}
+
+// CanAddSigner reports whether signers can be added to the document. Unknown statuses are left for the API to decide.
+func (s *Status) CanAddSigner() bool {
+	return !s.IsTerminal()
+}
+
+// CanDownload reports whether the file in the given FileFormat can be retrieved. The unsigned file is always available, the
+// others once they are listed in CompletedPackages.
+func (s *Status) CanDownload(format string) bool {
+	if format == FileFormatUnsigned {
+		return true
+	}
+
+	for _, p := range s.CompletedPackages {
+		if p == format {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StatusError is returned when an operation is refused locally because of the status of the document.
+type StatusError struct {
+	Operation      string
+	DocumentID     string
+	DocumentStatus string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("can't %s document %s with status %s", e.Operation, e.DocumentID, e.DocumentStatus)
+}
+
+// CancelDocument cancels a document. The status of the document is retrieved first, and a *StatusError is returned without
+// canceling if the document is already signed, canceled or expired.
+func (s *SignatureService) CancelDocument(ctx context.Context, documentID string, cancelReq *CancelDocumentRequest) error {
+	if err := s.checkStatus(ctx, documentID, "cancel", (*Status).CanCancel); err != nil {
+		return err
+	}
+
+	u := fmt.Sprintf("/signature/documents/%s/cancel", documentID)
+	req, err := s.client.NewRequest(http.MethodPost, u, cancelReq)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// UpdateDocument updates a document. Only fields set in the request are updated. The status of the document is retrieved first,
+// and a *StatusError is returned without updating if the document is already signed, canceled or expired.
+func (s *SignatureService) UpdateDocument(ctx context.Context, documentID string, updateReq *UpdateDocumentRequest) (*Document, error) {
+	if err := s.checkStatus(ctx, documentID, "update", (*Status).CanUpdate); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("/signature/documents/%s", documentID)
+	req, err := s.client.NewRequest(http.MethodPatch, u, updateReq)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(Document)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// AddSigner adds a signer to a document. The status of the document is retrieved first, and a *StatusError is returned without
+// adding the signer if the document is already signed, canceled or expired.
+func (s *SignatureService) AddSigner(ctx context.Context, documentID string, signerReq *SignerRequest) (*SignerResponse, error) {
+	if err := s.checkStatus(ctx, documentID, "add signer to", (*Status).CanAddSigner); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("/signature/documents/%s/signers", documentID)
+	req, err := s.client.NewRequest(http.MethodPost, u, signerReq)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(SignerResponse)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *SignatureService) checkStatus(ctx context.Context, documentID, operation string, allowed func(*Status) bool) error {
+	status, err := s.RetrieveDocumentStatus(ctx, documentID)
+	if err != nil {
+		return err
+	}
+	if !allowed(status) {
+		return &StatusError{Operation: operation, DocumentID: documentID, DocumentStatus: status.DocumentStatus}
+	}
+
+	return nil
+}
+
+// CancelDocumentRequest is the request body used to cancel a document.
+type CancelDocumentRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// UpdateDocumentRequest is the request body used to update a document. Only fields which are set are updated.
+type UpdateDocumentRequest struct {
+	Title          string          `json:"title,omitempty"`
+	Description    string          `json:"description,omitempty"`
+	ExternalID     string          `json:"externalId,omitempty"`
+	ContactDetails *ContactDetails `json:"contactDetails,omitempty"`
+	Advanced       *Advanced       `json:"advanced,omitempty"`
+}
@@ -0,0 +1,124 @@
+package signicat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestValidateTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		valid    bool
+	}{
+		{from: DocumentStatusUnsigned, to: DocumentStatusPartialSigned, valid: true},
+		{from: DocumentStatusPartialSigned, to: DocumentStatusSigned, valid: true},
+		{from: DocumentStatusUnsigned, to: DocumentStatusWaitingForAttachments, valid: true},
+		{from: DocumentStatusWaitingForAttachments, to: DocumentStatusUnsigned, valid: true},
+		{from: DocumentStatusPartialSigned, to: DocumentStatusCanceled, valid: true},
+		{from: DocumentStatusUnsigned, to: DocumentStatusExpired, valid: true},
+		{from: DocumentStatusSigned, to: DocumentStatusSigned, valid: true},
+		{from: DocumentStatusPartialSigned, to: DocumentStatusUnsigned},
+		{from: DocumentStatusSigned, to: DocumentStatusCanceled},
+		{from: DocumentStatusExpired, to: DocumentStatusUnsigned},
+		{from: "unknown", to: DocumentStatusSigned},
+	}
+
+	for _, test := range tests {
+		err := ValidateTransition(test.from, test.to)
+		assert.Equal(t, test.valid, err == nil, test.from+" -> "+test.to)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	status := &Status{DocumentStatus: DocumentStatusPartialSigned}
+	assert.False(t, status.IsTerminal())
+	assert.True(t, status.CanCancel())
+	assert.True(t, status.CanAddSigner())
+	assert.True(t, status.CanDownload(FileFormatUnsigned))
+	assert.False(t, status.CanDownload(FileFormatPades))
+
+	status = &Status{DocumentStatus: DocumentStatusSigned, CompletedPackages: []string{FileFormatPades}}
+	assert.True(t, status.IsTerminal())
+	assert.False(t, status.CanCancel())
+	assert.False(t, status.CanUpdate())
+	assert.False(t, status.CanAddSigner())
+	assert.True(t, status.CanDownload(FileFormatPades))
+	assert.False(t, status.CanDownload(FileFormatXades))
+}
+
+func TestSignatureService_CancelDocument(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	status := DocumentStatusUnsigned
+	canceled := false
+	mux.HandleFunc("/signature/documents/someDocumentId/status", func(res http.ResponseWriter, req *http.Request) {
+		if _, err := io.WriteString(res, `{"documentStatus":"`+status+`"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+	mux.HandleFunc("/signature/documents/someDocumentId/cancel", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+
+		var body CancelDocumentRequest
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		assert.Equal(t, "Wrong document", body.Reason)
+		canceled = true
+	})
+
+	err := client.Signature.CancelDocument(context.Background(), "someDocumentId", &CancelDocumentRequest{Reason: "Wrong document"})
+	assert.NoError(t, err)
+	assert.True(t, canceled)
+
+	canceled, status = false, DocumentStatusSigned
+	err = client.Signature.CancelDocument(context.Background(), "someDocumentId", &CancelDocumentRequest{Reason: "Wrong document"})
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, DocumentStatusSigned, statusErr.DocumentStatus)
+	assert.False(t, canceled)
+}
+
+func TestSignatureService_UpdateDocument(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/signature/documents/someDocumentId/status", func(res http.ResponseWriter, req *http.Request) {
+		if _, err := io.WriteString(res, `{"documentStatus":"unsigned"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+	mux.HandleFunc("/signature/documents/someDocumentId", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPatch, req.Method)
+		if _, err := io.Copy(res, req.Body); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	document, err := client.Signature.UpdateDocument(context.Background(), "someDocumentId", &UpdateDocumentRequest{Title: "New title"})
+	assert.NoError(t, err)
+	assert.Equal(t, "New title", document.Title)
+}
+
+func TestSignatureService_AddSigner(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/signature/documents/someDocumentId/status", func(res http.ResponseWriter, req *http.Request) {
+		if _, err := io.WriteString(res, `{"documentStatus":"expired"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+	mux.HandleFunc("/signature/documents/someDocumentId/signers", func(res http.ResponseWriter, req *http.Request) {
+		t.Fatal("signer added to expired document")
+	})
+
+	_, err := client.Signature.AddSigner(context.Background(), "someDocumentId", &SignerRequest{ExternalSignerID: "someSigner"})
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, "can't add signer to document someDocumentId with status expired", err.Error())
+}
@@ -0,0 +1,142 @@
+package native
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/larwef/signicat/internal/cms"
+	"github.com/larwef/signicat/internal/xmldsig"
+)
+
+// ErrNoSignatures is returned when a native file has no signatures.
+var ErrNoSignatures = errors.New("no signatures found")
+
+// DecodeSEIDSDO decodes a SEID-SDO, the native format of Norwegian BankID and Buypass. Each signature in it is a CMS signature,
+// detached from the signed document, in a CMSSignature element.
+func DecodeSEIDSDO(data []byte) ([]*Signature, error) {
+	encoded, err := elementText(data, "CMSSignature")
+	if err != nil {
+		return nil, err
+	}
+
+	var signatures []*Signature
+	for _, e := range encoded {
+		der, err := decodeBase64(e)
+		if err != nil {
+			return nil, fmt.Errorf("cms signature: %v", err)
+		}
+		sd, err := cms.Parse(der)
+		if err != nil {
+			return nil, fmt.Errorf("cms signature: %v", err)
+		}
+
+		for _, signer := range sd.Signers {
+			var chain []*x509.Certificate
+			if signer.Certificate != nil {
+				chain = append(chain, signer.Certificate)
+			}
+			chain = xmldsig.OrderChain(append(chain, sd.Certificates...))
+
+			signatures = append(signatures, (&Signature{
+				DigestAlgorithm: cms.DigestAlgorithm(signer.DigestAlgorithm),
+				Digest:          signer.MessageDigest,
+				SignedTime:      signer.SigningTime,
+			}).withCertificates(chain))
+		}
+	}
+	if len(signatures) == 0 {
+		return nil, ErrNoSignatures
+	}
+
+	return signatures, nil
+}
+
+// DecodeSwedishBankID decodes the native format of Swedish BankID, an XML signature over a bankIdSignedData element which holds
+// the text shown to the signer in usrVisibleData.
+func DecodeSwedishBankID(data []byte) ([]*Signature, error) {
+	signatures, err := DecodeXMLDSig(data)
+	if err != nil {
+		return nil, err
+	}
+
+	visible, err := elementText(data, "usrVisibleData")
+	if err != nil {
+		return nil, err
+	}
+	if len(visible) > 0 {
+		text, err := decodeBase64(visible[0])
+		if err != nil {
+			return nil, fmt.Errorf("usrVisibleData: %v", err)
+		}
+		for _, s := range signatures {
+			s.VisibleText = string(text)
+		}
+	}
+
+	return signatures, nil
+}
+
+// DecodeXMLDSig decodes native formats which are plain XML signatures, like the one of NemID. The digest is the one of the first
+// reference to signed data.
+func DecodeXMLDSig(data []byte) ([]*Signature, error) {
+	parsed, err := xmldsig.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed) == 0 {
+		return nil, ErrNoSignatures
+	}
+
+	var signatures []*Signature
+	for _, p := range parsed {
+		s := &Signature{SignedTime: p.SigningTime}
+		if s.SignedTime == nil && len(p.Timestamps) > 0 {
+			s.SignedTime = &p.Timestamps[0].Time
+		}
+		if refs := p.DataReferences(); len(refs) > 0 {
+			s.DigestAlgorithm, _ = xmldsig.DigestMethod(refs[0].DigestMethod)
+			s.Digest = refs[0].DigestValue
+		}
+
+		signatures = append(signatures, s.withCertificates(p.Certificates))
+	}
+
+	return signatures, nil
+}
+
+// elementText returns the text of every element with the local name, in any namespace.
+func elementText(data []byte, local string) ([]string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	var texts []string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return texts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != local {
+			continue
+		}
+
+		var text string
+		if err := dec.DecodeElement(&text, &start); err != nil {
+			return nil, err
+		}
+		texts = append(texts, text)
+	}
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+}
@@ -0,0 +1,133 @@
+// Package native decodes the signature files retrieved with signicat.FileFormatNative. The format of the file depends on the
+// signature method, so decoders are registered per signature method, and every decoder returns the same Signature type.
+//
+// Decoders are registered for Norwegian BankID, Buypass, Swedish BankID and Danish NemID. The Finnish signature methods and SMS
+// OTP have no decoder, as their native formats aren't documented, and Decode returns ErrUnsupportedMethod for them. A decoder
+// can be registered for them with Register.
+//
+// The signatures are read, not verified.
+package native
+
+import (
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/larwef/signicat"
+)
+
+// ErrUnsupportedMethod is returned when there is no decoder for a signature method.
+var ErrUnsupportedMethod = errors.New("no decoder for signature method")
+
+// Signature is a signature read from a native file.
+type Signature struct {
+	// Name is the name of the signer, and UniqueID the identifier of the signer in the signature method, eg. the BankID PID. Both
+	// are taken from the certificate.
+	Name     string
+	UniqueID string
+	// Certificate is the certificate of the signer, and Chain the rest of the certificates in the file, ordered towards the root.
+	Certificate *x509.Certificate
+	Chain       []*x509.Certificate
+	// DigestAlgorithm and Digest are the hash of the data which was signed. DigestAlgorithm is 0 if the algorithm is unknown.
+	DigestAlgorithm crypto.Hash
+	Digest          []byte
+	// SignedTime is the signing time claimed in the signature, if any.
+	SignedTime *time.Time
+	// VisibleText is the text shown to the signer while signing, for signature methods which show one.
+	VisibleText string
+}
+
+// Decoder decodes a native signature file.
+type Decoder interface {
+	Decode(data []byte) ([]*Signature, error)
+}
+
+// DecoderFunc is a function used as a Decoder.
+type DecoderFunc func(data []byte) ([]*Signature, error)
+
+// Decode calls f(data).
+func (f DecoderFunc) Decode(data []byte) ([]*Signature, error) {
+	return f(data)
+}
+
+// undocumented are the signature methods with no decoder in this package, because their native formats aren't documented.
+var undocumented = map[string]bool{
+	signicat.SignatureMethodFiTupas:           true,
+	signicat.SignatureMethodFiMobiilivarmenne: true,
+	signicat.SignatureMethodFiEid:             true,
+	signicat.SignatureMethodSmsOtp:            true,
+}
+
+// Registry holds the decoders for each signature method.
+type Registry struct {
+	mu       sync.RWMutex
+	decoders map[string]Decoder
+}
+
+// NewRegistry returns a registry with the decoders in this package registered.
+func NewRegistry() *Registry {
+	r := &Registry{decoders: make(map[string]Decoder)}
+
+	r.Register(signicat.SignatureMethodNoBankIDNetCentric, DecoderFunc(DecodeSEIDSDO))
+	r.Register(signicat.SignatureMethodNoBankIDMobile, DecoderFunc(DecodeSEIDSDO))
+	r.Register(signicat.SignatureMethodNoBuypass, DecoderFunc(DecodeSEIDSDO))
+	r.Register(signicat.SignatureMethodSeBankID, DecoderFunc(DecodeSwedishBankID))
+	r.Register(signicat.SignatureMethodDkNemID, DecoderFunc(DecodeXMLDSig))
+
+	return r
+}
+
+// Register sets the decoder for a signature method, replacing any existing one.
+func (r *Registry) Register(signatureMethod string, decoder Decoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.decoders[signatureMethod] = decoder
+}
+
+// Decode decodes a native file signed with the signature method. Returns ErrUnsupportedMethod if there is no decoder for it.
+func (r *Registry) Decode(signatureMethod string, data []byte) ([]*Signature, error) {
+	r.mu.RLock()
+	decoder, ok := r.decoders[signatureMethod]
+	r.mu.RUnlock()
+
+	if !ok {
+		if undocumented[signatureMethod] {
+			return nil, fmt.Errorf("%w: %s, its native format isn't documented, register a decoder for it", ErrUnsupportedMethod,
+				signatureMethod)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMethod, signatureMethod)
+	}
+
+	return decoder.Decode(data)
+}
+
+// DefaultRegistry is the registry used by Register and Decode.
+var DefaultRegistry = NewRegistry()
+
+// Register sets the decoder for a signature method in DefaultRegistry.
+func Register(signatureMethod string, decoder Decoder) {
+	DefaultRegistry.Register(signatureMethod, decoder)
+}
+
+// Decode decodes a native file with the decoders in DefaultRegistry.
+func Decode(signatureMethod string, data []byte) ([]*Signature, error) {
+	return DefaultRegistry.Decode(signatureMethod, data)
+}
+
+// withCertificates sets the certificate fields of s from a chain ordered from the leaf.
+func (s *Signature) withCertificates(chain []*x509.Certificate) *Signature {
+	if len(chain) == 0 {
+		return s
+	}
+
+	s.Certificate = chain[0]
+	s.Chain = chain[1:]
+	s.Name = s.Certificate.Subject.CommonName
+	s.UniqueID = s.Certificate.Subject.SerialNumber
+
+	return s
+}
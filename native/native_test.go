@@ -0,0 +1,126 @@
+package native
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/larwef/signicat"
+	"github.com/larwef/signicat/internal/testpki"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode_SEIDSDO(t *testing.T) {
+	document := []byte("%PDF-1.7 contract")
+	digest := sha256.Sum256(document)
+	ola, ca := testpki.Chain("Ola Nordmann", "9578-6000-4-123456")
+	signed := time.Date(2020, 6, 26, 12, 0, 0, 0, time.UTC)
+
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<seidsdo:SEIDSDO xmlns:seidsdo="http://www.npt.no/seid/xmlskjema/SDO_v1.0" xmlns:xades="http://www.npt.no/seid/xmlskjema/XAdES_v1.0">
+  <seidsdo:SignersDocumentSignature>
+    <xades:CMSSignatureElement><xades:CMSSignature>` +
+		base64.StdEncoding.EncodeToString(testpki.SignedData(document, signed, ola, ca)) + `</xades:CMSSignature></xades:CMSSignatureElement>
+  </seidsdo:SignersDocumentSignature>
+</seidsdo:SEIDSDO>`)
+
+	for _, method := range []string{
+		signicat.SignatureMethodNoBankIDNetCentric,
+		signicat.SignatureMethodNoBankIDMobile,
+		signicat.SignatureMethodNoBuypass,
+	} {
+		signatures, err := Decode(method, data)
+		assert.NoError(t, err, method)
+		assert.Equal(t, []*Signature{{
+			Name:            "Ola Nordmann",
+			UniqueID:        "9578-6000-4-123456",
+			Certificate:     ola,
+			Chain:           []*x509.Certificate{ca},
+			DigestAlgorithm: crypto.SHA256,
+			Digest:          digest[:],
+			SignedTime:      &signed,
+		}}, signatures, method)
+	}
+}
+
+func TestDecode_SwedishBankID(t *testing.T) {
+	signedData := []byte("<bankIdSignedData>signed</bankIdSignedData>")
+	digest := sha256.Sum256(signedData)
+	anna, ca := testpki.Chain("Anna Andersson", "198112289874")
+
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<sig:Signature xmlns:sig="http://www.example.com/bankid">` +
+		testpki.Signature(&testpki.SignatureOptions{
+			ID: "bankid", Document: signedData, DocumentURI: "#bidSignedData", Certificates: []*x509.Certificate{ca, anna},
+		}) + `
+  <bankIdSignedData Id="bidSignedData">
+    <usrVisibleData charset="UTF-8" visible="wysiwys">` + base64.StdEncoding.EncodeToString([]byte("Jag godkänner avtalet")) + `</usrVisibleData>
+  </bankIdSignedData>
+</sig:Signature>`)
+
+	signatures, err := Decode(signicat.SignatureMethodSeBankID, data)
+	assert.NoError(t, err)
+	assert.Len(t, signatures, 1)
+
+	s := signatures[0]
+	assert.Equal(t, "Anna Andersson", s.Name)
+	assert.Equal(t, "198112289874", s.UniqueID)
+	assert.Equal(t, anna, s.Certificate)
+	assert.Equal(t, []*x509.Certificate{ca}, s.Chain)
+	assert.Equal(t, crypto.SHA256, s.DigestAlgorithm)
+	assert.Equal(t, digest[:], s.Digest)
+	assert.Equal(t, "Jag godkänner avtalet", s.VisibleText)
+}
+
+func TestDecode_NemID(t *testing.T) {
+	document := []byte("signed text")
+	digest := sha256.Sum256(document)
+	lars, _ := testpki.Chain("Lars Hansen", "PID:9208-2002-2-123456789012")
+	stamped := time.Date(2020, 6, 26, 12, 0, 0, 0, time.UTC)
+
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>` + testpki.Signature(&testpki.SignatureOptions{
+		ID: "nemid", Document: document, Certificates: []*x509.Certificate{lars}, Timestamp: &stamped,
+	}))
+
+	signatures, err := Decode(signicat.SignatureMethodDkNemID, data)
+	assert.NoError(t, err)
+	assert.Len(t, signatures, 1)
+	assert.Equal(t, "Lars Hansen", signatures[0].Name)
+	assert.Equal(t, "PID:9208-2002-2-123456789012", signatures[0].UniqueID)
+	assert.Equal(t, digest[:], signatures[0].Digest)
+	assert.Equal(t, stamped, *signatures[0].SignedTime)
+}
+
+func TestDecode_NoSignatures(t *testing.T) {
+	_, err := Decode(signicat.SignatureMethodNoBankIDMobile, []byte(`<SEIDSDO/>`))
+	assert.Equal(t, ErrNoSignatures, err)
+
+	_, err = Decode(signicat.SignatureMethodDkNemID, []byte(`<root/>`))
+	assert.Equal(t, ErrNoSignatures, err)
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	_, err := r.Decode(signicat.SignatureMethodFiTupas, nil)
+	assert.True(t, errors.Is(err, ErrUnsupportedMethod))
+	assert.EqualError(t, err, "no decoder for signature method: fi_tupas, its native format isn't documented, register a decoder for it")
+	_, err = r.Decode("some_method", nil)
+	assert.EqualError(t, err, "no decoder for signature method: some_method")
+
+	want := []*Signature{{Name: "Matti Meikäläinen"}}
+	r.Register(signicat.SignatureMethodFiTupas, DecoderFunc(func(data []byte) ([]*Signature, error) {
+		return want, nil
+	}))
+	signatures, err := r.Decode(signicat.SignatureMethodFiTupas, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, want, signatures)
+
+	// The default registry is not affected.
+	_, err = Decode(signicat.SignatureMethodFiTupas, nil)
+	assert.True(t, errors.Is(err, ErrUnsupportedMethod))
+}
@@ -0,0 +1,337 @@
+// Package nin validates the national identity numbers of the Nordic countries Signicat supports signing in: the Norwegian
+// fødselsnummer and D-nummer, the Swedish personnummer and samordningsnummer, the Danish CPR number and the Finnish personal
+// identity code (HETU).
+package nin
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/larwef/signicat/internal/checkdigit"
+)
+
+// Supported countries, as ISO 3166-1 alpha-2 codes.
+const (
+	Norway  = "NO"
+	Sweden  = "SE"
+	Denmark = "DK"
+	Finland = "FI"
+)
+
+// Kinds of numbers.
+const (
+	// KindPersonal is the number of a resident, ie. a fødselsnummer, personnummer, CPR number or HETU.
+	KindPersonal = "personal"
+	// KindDNumber is a Norwegian D-nummer, given to people who aren't residents.
+	KindDNumber = "d-number"
+	// KindCoordination is a Swedish samordningsnummer, given to people who aren't residents.
+	KindCoordination = "coordination"
+)
+
+// Gender is the legal gender encoded in a number.
+type Gender string
+
+// Genders.
+const (
+	Female Gender = "female"
+	Male   Gender = "male"
+)
+
+// ErrUnsupportedCountry is returned for countries this package doesn't know the numbers of.
+var ErrUnsupportedCountry = errors.New("unsupported country")
+
+// InvalidError tells why a number is invalid.
+type InvalidError struct {
+	Country string
+	Reason  string
+}
+
+func (e *InvalidError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", numberNames[e.Country], e.Reason)
+}
+
+var numberNames = map[string]string{
+	Norway:  "fødselsnummer",
+	Sweden:  "personnummer",
+	Denmark: "CPR number",
+	Finland: "HETU",
+}
+
+// Number is a parsed national identity number.
+type Number struct {
+	Country string
+	Kind    string
+	// Canonical is the number in the form Signicat and the electronic IDs use: 11 digits for Norway, 12 digits (YYYYMMDDNNNC)
+	// for Sweden, 10 digits for Denmark and the HETU with its century sign for Finland.
+	Canonical string
+	BirthDate time.Time
+	Gender    Gender
+}
+
+// now is the time Swedish numbers without the century are interpreted relative to.
+var now = time.Now
+
+var parsers = map[string]func(s string) (*Number, error){
+	Norway:  parseNorwegian,
+	Sweden:  parseSwedish,
+	Denmark: parseDanish,
+	Finland: parseFinnish,
+}
+
+// Parse parses the national identity number of a country. Spaces are ignored. Returns an *InvalidError if the number is invalid,
+// and ErrUnsupportedCountry if the country isn't one of the supported.
+func Parse(country, s string) (*Number, error) {
+	parse, ok := parsers[strings.ToUpper(country)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCountry, country)
+	}
+
+	return parse(strings.Join(strings.Fields(s), ""))
+}
+
+// Validate checks the national identity number of a country. See Parse.
+func Validate(country, s string) error {
+	_, err := Parse(country, s)
+	return err
+}
+
+// Supported reports whether there is validation for the numbers of a country.
+func Supported(country string) bool {
+	_, ok := parsers[strings.ToUpper(country)]
+	return ok
+}
+
+// parseNorwegian parses DDMMYYIIIKK. A D-nummer has 4 added to the first digit of the day.
+func parseNorwegian(s string) (*Number, error) {
+	invalid := func(format string, a ...interface{}) error {
+		return &InvalidError{Country: Norway, Reason: fmt.Sprintf(format, a...)}
+	}
+
+	d, ok := checkdigit.Digits(s)
+	if !ok || len(d) != 11 {
+		return nil, invalid("must be 11 digits")
+	}
+
+	k1 := mod11(d[:9], []int{3, 7, 6, 1, 8, 9, 4, 5, 2})
+	k2 := mod11(d[:10], []int{5, 4, 3, 2, 7, 6, 5, 4, 3, 2})
+	if k1 != d[9] || k2 != d[10] {
+		return nil, invalid("check digits are wrong")
+	}
+
+	number := &Number{Country: Norway, Kind: KindPersonal, Canonical: s, Gender: Female}
+	day := d[0]*10 + d[1]
+	if day > 40 {
+		number.Kind = KindDNumber
+		day -= 40
+	}
+	month, yy, individual := d[2]*10+d[3], d[4]*10+d[5], d[6]*100+d[7]*10+d[8]
+
+	var year int
+	switch {
+	case individual < 500:
+		year = 1900 + yy
+	case individual < 750 && yy >= 54:
+		year = 1800 + yy
+	case yy < 40:
+		year = 2000 + yy
+	case individual >= 900:
+		year = 1900 + yy
+	default:
+		return nil, invalid("individual number %03d isn't used for year %02d", individual, yy)
+	}
+
+	var err error
+	if number.BirthDate, err = date(year, month, day); err != nil {
+		return nil, invalid("%v", err)
+	}
+	if d[8]%2 == 1 {
+		number.Gender = Male
+	}
+
+	return number, nil
+}
+
+// parseSwedish parses YYMMDD-NNNC, where the separator is + for people 100 years or older, or YYYYMMDDNNNC with an optional
+// separator. A samordningsnummer has 60 added to the day.
+func parseSwedish(s string) (*Number, error) {
+	invalid := func(format string, a ...interface{}) error {
+		return &InvalidError{Country: Sweden, Reason: fmt.Sprintf(format, a...)}
+	}
+
+	plus := false
+	if i := strings.IndexAny(s, "-+"); i >= 0 && i == len(s)-5 {
+		plus = s[i] == '+'
+		s = s[:i] + s[i+1:]
+	}
+	d, ok := checkdigit.Digits(s)
+	if !ok || (len(d) != 10 && len(d) != 12) {
+		return nil, invalid("must be 10 or 12 digits")
+	}
+
+	century := -1
+	if len(d) == 12 {
+		century = d[0]*10 + d[1]
+		d = d[2:]
+	}
+	if !checkdigit.Luhn(d) {
+		return nil, invalid("check digit is wrong")
+	}
+
+	number := &Number{Country: Sweden, Kind: KindPersonal, Gender: Female}
+	yy, month, day := d[0]*10+d[1], d[2]*10+d[3], d[4]*10+d[5]
+	if day > 60 {
+		number.Kind = KindCoordination
+		day -= 60
+	}
+
+	year := century*100 + yy
+	if century < 0 {
+		// The most recent year the person can have been born, which is a century earlier for the + separator.
+		t := now()
+		year = t.Year()/100*100 + yy
+		if birth, err := date(year, month, day); err == nil && birth.After(t) {
+			year -= 100
+		}
+		if plus {
+			year -= 100
+		}
+	}
+
+	var err error
+	if number.BirthDate, err = date(year, month, day); err != nil {
+		return nil, invalid("%v", err)
+	}
+	if d[8]%2 == 1 {
+		number.Gender = Male
+	}
+	number.Canonical = fmt.Sprintf("%02d", year/100) + joinDigits(d)
+
+	return number, nil
+}
+
+// parseDanish parses DDMMYY-SSSS. The modulus 11 check isn't done, as numbers issued since 2007 don't all pass it.
+func parseDanish(s string) (*Number, error) {
+	invalid := func(format string, a ...interface{}) error {
+		return &InvalidError{Country: Denmark, Reason: fmt.Sprintf(format, a...)}
+	}
+
+	if len(s) == 11 && s[6] == '-' {
+		s = s[:6] + s[7:]
+	}
+	d, ok := checkdigit.Digits(s)
+	if !ok || len(d) != 10 {
+		return nil, invalid("must be 10 digits")
+	}
+
+	day, month, yy := d[0]*10+d[1], d[2]*10+d[3], d[4]*10+d[5]
+
+	year := 1900 + yy
+	switch d[6] {
+	case 4, 9:
+		if yy <= 36 {
+			year = 2000 + yy
+		}
+	case 5, 6, 7, 8:
+		if yy <= 57 {
+			year = 2000 + yy
+		} else {
+			year = 1800 + yy
+		}
+	}
+
+	number := &Number{Country: Denmark, Kind: KindPersonal, Canonical: s, Gender: Female}
+	var err error
+	if number.BirthDate, err = date(year, month, day); err != nil {
+		return nil, invalid("%v", err)
+	}
+	if d[9]%2 == 1 {
+		number.Gender = Male
+	}
+
+	return number, nil
+}
+
+// Finnish century signs. The letters besides A and - were added in 2023.
+var finnishCenturies = map[byte]int{
+	'+': 1800,
+	'-': 1900, 'Y': 1900, 'X': 1900, 'W': 1900, 'V': 1900, 'U': 1900,
+	'A': 2000, 'B': 2000, 'C': 2000, 'D': 2000, 'E': 2000, 'F': 2000,
+}
+
+const finnishCheckCharacters = "0123456789ABCDEFHJKLMNPRSTUVWXY"
+
+// parseFinnish parses DDMMYYCZZZQ, where C is the century sign and Q the check character.
+func parseFinnish(s string) (*Number, error) {
+	invalid := func(format string, a ...interface{}) error {
+		return &InvalidError{Country: Finland, Reason: fmt.Sprintf(format, a...)}
+	}
+
+	s = strings.ToUpper(s)
+	if len(s) != 11 {
+		return nil, invalid("must be 11 characters")
+	}
+	century, ok := finnishCenturies[s[6]]
+	if !ok {
+		return nil, invalid("century sign %q is unknown", s[6])
+	}
+	d, ok := checkdigit.Digits(s[:6] + s[7:10])
+	if !ok {
+		return nil, invalid("must be digits except the century sign and the check character")
+	}
+
+	n := 0
+	for _, v := range d {
+		n = n*10 + v
+	}
+	if finnishCheckCharacters[n%31] != s[10] {
+		return nil, invalid("check character is wrong")
+	}
+
+	individual := d[6]*100 + d[7]*10 + d[8]
+	if individual < 2 {
+		return nil, invalid("individual number %03d isn't used", individual)
+	}
+
+	number := &Number{Country: Finland, Kind: KindPersonal, Canonical: s, Gender: Female}
+	var err error
+	if number.BirthDate, err = date(century+d[4]*10+d[5], d[2]*10+d[3], d[0]*10+d[1]); err != nil {
+		return nil, invalid("%v", err)
+	}
+	if individual%2 == 1 {
+		number.Gender = Male
+	}
+
+	return number, nil
+}
+
+func date(year, month, day int) (time.Time, error) {
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if t.Year() != year || int(t.Month()) != month || t.Day() != day {
+		return time.Time{}, fmt.Errorf("%04d-%02d-%02d is not a date", year, month, day)
+	}
+
+	return t, nil
+}
+
+func joinDigits(d []int) string {
+	b := make([]byte, len(d))
+	for i, v := range d {
+		b[i] = byte('0' + v)
+	}
+
+	return string(b)
+}
+
+// mod11 returns the modulus 11 check digit of d with the weights, or -1 if there is none.
+func mod11(d, weights []int) int {
+	switch k := 11 - checkdigit.WeightedSum(d, weights)%11; k {
+	case 11:
+		return 0
+	case 10:
+		return -1
+	default:
+		return k
+	}
+}
@@ -0,0 +1,73 @@
+package nin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	now = func() time.Time { return time.Date(2020, 6, 26, 0, 0, 0, 0, time.UTC) }
+
+	birth := func(year, month, day int) time.Time {
+		return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		country string
+		number  string
+		want    *Number
+	}{
+		{Norway, "010190 12480", &Number{Norway, KindPersonal, "01019012480", birth(1990, 1, 1), Female}},
+		{Norway, "41019012474", &Number{Norway, KindDNumber, "41019012474", birth(1990, 1, 1), Female}},
+		{Norway, "29022050115", &Number{Norway, KindPersonal, "29022050115", birth(2020, 2, 29), Male}},
+		{Norway, "15039998723", &Number{Norway, KindPersonal, "15039998723", birth(1999, 3, 15), Male}},
+		{Sweden, "811228-9874", &Number{Sweden, KindPersonal, "198112289874", birth(1981, 12, 28), Male}},
+		{Sweden, "198112289874", &Number{Sweden, KindPersonal, "198112289874", birth(1981, 12, 28), Male}},
+		{Sweden, "811228+9874", &Number{Sweden, KindPersonal, "188112289874", birth(1881, 12, 28), Male}},
+		{Sweden, "701063-2391", &Number{Sweden, KindCoordination, "197010632391", birth(1970, 10, 3), Male}},
+		{Denmark, "070761-4285", &Number{Denmark, KindPersonal, "0707614285", birth(1961, 7, 7), Male}},
+		{Denmark, "0101005554", &Number{Denmark, KindPersonal, "0101005554", birth(2000, 1, 1), Female}},
+		{Finland, "131052-308t", &Number{Finland, KindPersonal, "131052-308T", birth(1952, 10, 13), Female}},
+		{Finland, "010594Y9032", &Number{Finland, KindPersonal, "010594Y9032", birth(1994, 5, 1), Male}},
+		{"no", "01019012480", &Number{Norway, KindPersonal, "01019012480", birth(1990, 1, 1), Female}},
+	}
+
+	for _, test := range tests {
+		number, err := Parse(test.country, test.number)
+		assert.NoError(t, err, test.number)
+		assert.Equal(t, test.want, number, test.number)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		country string
+		number  string
+		err     string
+	}{
+		{Norway, "0101901248", "invalid fødselsnummer: must be 11 digits"},
+		{Norway, "01019012481", "invalid fødselsnummer: check digits are wrong"},
+		{Norway, "3102901234x", "invalid fødselsnummer: must be 11 digits"},
+		{Sweden, "811228-9875", "invalid personnummer: check digit is wrong"},
+		{Sweden, "81122898", "invalid personnummer: must be 10 or 12 digits"},
+		{Denmark, "320161-4285", "invalid CPR number: 1961-01-32 is not a date"},
+		{Finland, "131052-308U", "invalid HETU: check character is wrong"},
+		{Finland, "131052G308T", `invalid HETU: century sign 'G' is unknown`},
+	}
+
+	for _, test := range tests {
+		err := Validate(test.country, test.number)
+		var invalidErr *InvalidError
+		assert.True(t, errors.As(err, &invalidErr), test.number)
+		assert.EqualError(t, err, test.err, test.number)
+	}
+
+	err := Validate("IS", "1234567890")
+	assert.True(t, errors.Is(err, ErrUnsupportedCountry))
+	assert.False(t, Supported("IS"))
+	assert.True(t, Supported("fi"))
+}
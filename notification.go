@@ -0,0 +1,327 @@
+package signicat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Available notification types.
+const (
+	NotificationTypeSignRequest      = "signRequest"
+	NotificationTypeReminder         = "reminder"
+	NotificationTypeSignatureReceipt = "signatureReceipt"
+	NotificationTypeFinalReceipt     = "finalReceipt"
+	NotificationTypeCanceledReceipt  = "canceledReceipt"
+	NotificationTypeExpiredReceipt   = "expiredReceipt"
+
+	// Available notification channels.
+	NotificationChannelEmail = "email"
+	NotificationChannelSms   = "sms"
+
+	// Available notification delivery statuses.
+	NotificationStatusSent      = "sent"
+	NotificationStatusDelivered = "delivered"
+	NotificationStatusFailed    = "failed"
+)
+
+// RetrieveNotificationSettings retrieves the account level notification settings. These are used for every document which doesn't
+// override them in its Notification.
+func (s *SignatureService) RetrieveNotificationSettings(ctx context.Context) (*NotificationSettings, error) {
+	req, err := s.client.NewRequest(http.MethodGet, "/signature/notification-settings", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(NotificationSettings)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// UpdateNotificationSettings replaces the account level notification settings.
+func (s *SignatureService) UpdateNotificationSettings(ctx context.Context, settings *NotificationSettings) (*NotificationSettings, error) {
+	req, err := s.client.NewRequest(http.MethodPut, "/signature/notification-settings", settings)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(NotificationSettings)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// PreviewNotification renders a notification the way Signicat would send it, using the account level settings and the merge field
+// values in the request.
+func (s *SignatureService) PreviewNotification(ctx context.Context, previewReq *NotificationPreviewRequest) (*NotificationPreview, error) {
+	req, err := s.client.NewRequest(http.MethodPost, "/signature/notification-settings/preview", previewReq)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(NotificationPreview)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ResendNotification sends the sign request or a reminder to a signer again. NotificationType must be NotificationTypeSignRequest
+// or NotificationTypeReminder.
+func (s *SignatureService) ResendNotification(ctx context.Context, documentID, signerID string, resendReq *ResendNotificationRequest) error {
+	if resendReq == nil {
+		return errors.New("no resend notification request")
+	}
+	switch resendReq.NotificationType {
+	case NotificationTypeSignRequest, NotificationTypeReminder:
+	default:
+		return fmt.Errorf("notification type %q can't be resent", resendReq.NotificationType)
+	}
+	if resendReq.Setup == NotificationSetupOff {
+		return fmt.Errorf("notification setup %q doesn't send anything", resendReq.Setup)
+	}
+
+	u := fmt.Sprintf("/signature/documents/%s/signers/%s/notify", documentID, signerID)
+	req, err := s.client.NewRequest(http.MethodPost, u, resendReq)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Do(ctx, req, nil)
+}
+
+// ListNotifications lists the notifications sent to a signer.
+func (s *SignatureService) ListNotifications(ctx context.Context, documentID, signerID string) ([]*NotificationLogEntry, error) {
+	u := fmt.Sprintf("/signature/documents/%s/signers/%s/notifications", documentID, signerID)
+	req, err := s.client.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []*NotificationLogEntry
+	if err := s.client.Do(ctx, req, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ResendNotificationRequest is the request body used to resend a notification to a signer. Setup is one of the NotificationSetup
+// constants, and defaults to the setup of the signer if empty. Email and Sms override the texts of the document.
+type ResendNotificationRequest struct {
+	NotificationType string `json:"notificationType"`
+	Setup            string `json:"setup,omitempty"`
+	Email            *Email `json:"email,omitempty"`
+	Sms              *Sms   `json:"sms,omitempty"`
+}
+
+// NotificationLogEntry is a notification sent to a signer.
+type NotificationLogEntry struct {
+	ID               string     `json:"id,omitempty"`
+	NotificationType string     `json:"notificationType,omitempty"`
+	Channel          string     `json:"channel,omitempty"`
+	Recipient        string     `json:"recipient,omitempty"`
+	Language         string     `json:"language,omitempty"`
+	Subject          string     `json:"subject,omitempty"`
+	Text             string     `json:"text,omitempty"`
+	Status           string     `json:"status,omitempty"`
+	Error            string     `json:"error,omitempty"`
+	Sent             *time.Time `json:"sent,omitempty"`
+}
+
+// NotificationSettings is the account level notification settings.
+type NotificationSettings struct {
+	SenderName   string        `json:"senderName,omitempty"`
+	SmsSender    string        `json:"smsSender,omitempty"`
+	Notification *Notification `json:"notification,omitempty"`
+}
+
+// NotificationPreviewRequest is the request body used to preview a notification. MergeFields is keyed by the MergeField constants.
+type NotificationPreviewRequest struct {
+	NotificationType string            `json:"notificationType"`
+	Language         string            `json:"language"`
+	MergeFields      map[string]string `json:"mergeFields,omitempty"`
+}
+
+// NotificationPreview is a rendered notification.
+type NotificationPreview struct {
+	Email *Email `json:"email,omitempty"`
+	Sms   *Sms   `json:"sms,omitempty"`
+}
+
+// NotificationTemplates is a local registry of notification texts per notification type and language. It is used to build the
+// Notification of a CreateDocumentRequest without repeating the texts, and to preview what the signers will receive.
+type NotificationTemplates struct {
+	email map[string][]*Email
+	sms   map[string][]*Sms
+}
+
+// NewNotificationTemplates returns an empty template registry.
+func NewNotificationTemplates() *NotificationTemplates {
+	return &NotificationTemplates{
+		email: make(map[string][]*Email),
+		sms:   make(map[string][]*Sms),
+	}
+}
+
+// NotificationTemplatesFrom returns a template registry holding the texts in n.
+func NotificationTemplatesFrom(n *Notification) *NotificationTemplates {
+	t := NewNotificationTemplates()
+	if n == nil {
+		return t
+	}
+
+	add := func(notificationType string, emails []*Email, sms []*Sms) {
+		for _, e := range emails {
+			t.SetEmail(notificationType, e)
+		}
+		for _, s := range sms {
+			t.SetSms(notificationType, s)
+		}
+	}
+
+	if n.SignRequest != nil {
+		add(NotificationTypeSignRequest, n.SignRequest.Email, n.SignRequest.Sms)
+	}
+	if n.Reminder != nil {
+		add(NotificationTypeReminder, n.Reminder.Email, n.Reminder.Sms)
+	}
+	if n.SignatureReceipt != nil {
+		add(NotificationTypeSignatureReceipt, n.SignatureReceipt.Email, n.SignatureReceipt.Sms)
+	}
+	if n.FinalReceipt != nil {
+		add(NotificationTypeFinalReceipt, n.FinalReceipt.Email, n.FinalReceipt.Sms)
+	}
+	if n.CanceledReceipt != nil {
+		add(NotificationTypeCanceledReceipt, n.CanceledReceipt.Email, n.CanceledReceipt.Sms)
+	}
+	if n.ExpiredReceipt != nil {
+		add(NotificationTypeExpiredReceipt, n.ExpiredReceipt.Email, n.ExpiredReceipt.Sms)
+	}
+
+	return t
+}
+
+// SetEmail registers the email template for a notification type in the language of email. An existing template for the same type
+// and language is replaced.
+func (t *NotificationTemplates) SetEmail(notificationType string, email *Email) {
+	for i, e := range t.email[notificationType] {
+		if e.Language == email.Language {
+			t.email[notificationType][i] = email
+			return
+		}
+	}
+	t.email[notificationType] = append(t.email[notificationType], email)
+}
+
+// SetSms registers the sms template for a notification type in the language of sms. An existing template for the same type and
+// language is replaced.
+func (t *NotificationTemplates) SetSms(notificationType string, sms *Sms) {
+	for i, s := range t.sms[notificationType] {
+		if s.Language == sms.Language {
+			t.sms[notificationType][i] = sms
+			return
+		}
+	}
+	t.sms[notificationType] = append(t.sms[notificationType], sms)
+}
+
+// Email returns the email template for a notification type and language, or nil if there is none.
+func (t *NotificationTemplates) Email(notificationType, language string) *Email {
+	for _, e := range t.email[notificationType] {
+		if e.Language == language {
+			return e
+		}
+	}
+	return nil
+}
+
+// Sms returns the sms template for a notification type and language, or nil if there is none.
+func (t *NotificationTemplates) Sms(notificationType, language string) *Sms {
+	for _, s := range t.sms[notificationType] {
+		if s.Language == language {
+			return s
+		}
+	}
+	return nil
+}
+
+// Notification builds a Notification holding the registered texts. Only the notification types with at least one template are set.
+// Settings which are not texts, eg. Reminder.ChronSchedule, has to be set by the caller.
+func (t *NotificationTemplates) Notification() *Notification {
+	n := &Notification{}
+
+	if t.has(NotificationTypeSignRequest) {
+		n.SignRequest = &SignRequest{Email: t.email[NotificationTypeSignRequest], Sms: t.sms[NotificationTypeSignRequest]}
+	}
+	if t.has(NotificationTypeReminder) {
+		n.Reminder = &Reminder{Email: t.email[NotificationTypeReminder], Sms: t.sms[NotificationTypeReminder]}
+	}
+	if t.has(NotificationTypeSignatureReceipt) {
+		n.SignatureReceipt = &SignatureReceipt{Email: t.email[NotificationTypeSignatureReceipt], Sms: t.sms[NotificationTypeSignatureReceipt]}
+	}
+	if t.has(NotificationTypeFinalReceipt) {
+		n.FinalReceipt = &FinalReceipt{Email: t.email[NotificationTypeFinalReceipt], Sms: t.sms[NotificationTypeFinalReceipt]}
+	}
+	if t.has(NotificationTypeCanceledReceipt) {
+		n.CanceledReceipt = &CanceledReceipt{Email: t.email[NotificationTypeCanceledReceipt], Sms: t.sms[NotificationTypeCanceledReceipt]}
+	}
+	if t.has(NotificationTypeExpiredReceipt) {
+		n.ExpiredReceipt = &ExpiredReceipt{Email: t.email[NotificationTypeExpiredReceipt], Sms: t.sms[NotificationTypeExpiredReceipt]}
+	}
+
+	return n
+}
+
+// Preview renders the templates for a notification type and language with the merge field values, which are keyed by the
+// MergeField constants. Either of the returned values are nil if there is no template for it.
+func (t *NotificationTemplates) Preview(notificationType, language string, values map[string]string) *NotificationPreview {
+	preview := &NotificationPreview{}
+
+	if e := t.Email(notificationType, language); e != nil {
+		preview.Email = &Email{
+			Language:   e.Language,
+			Subject:    RenderMergeFields(e.Subject, values),
+			Text:       RenderMergeFields(e.Text, values),
+			SenderName: e.SenderName,
+		}
+	}
+
+	if s := t.Sms(notificationType, language); s != nil {
+		preview.Sms = &Sms{
+			Language: s.Language,
+			Text:     RenderMergeFields(s.Text, values),
+			Sender:   s.Sender,
+		}
+	}
+
+	return preview
+}
+
+func (t *NotificationTemplates) has(notificationType string) bool {
+	return len(t.email[notificationType]) > 0 || len(t.sms[notificationType]) > 0
+}
+
+// RenderMergeFields replaces the merge fields in text with the values, which are keyed by the MergeField constants, eg.
+// MergeFieldDocumentTitle. Merge fields without a value are left as is.
+func RenderMergeFields(text string, values map[string]string) string {
+	if len(values) == 0 {
+		return text
+	}
+
+	oldnew := make([]string, 0, len(values)*2)
+	for field, value := range values {
+		oldnew = append(oldnew, field, value)
+	}
+
+	return strings.NewReplacer(oldnew...).Replace(text)
+}
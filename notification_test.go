@@ -0,0 +1,162 @@
+package signicat
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestSignatureService_RetrieveNotificationSettings(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, "/signature/notification-settings", req.URL.Path)
+		if _, err := io.WriteString(res, `{"senderName":"Sender","notification":{"signRequest":{"email":[{"language":"EN","subject":"Sign"}]}}}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	settings, err := client.Signature.RetrieveNotificationSettings(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "Sender", settings.SenderName)
+	assert.Equal(t, "Sign", settings.Notification.SignRequest.Email[0].Subject)
+}
+
+func TestSignatureService_UpdateNotificationSettings(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPut, req.Method)
+		assert.Equal(t, "/signature/notification-settings", req.URL.Path)
+		if _, err := io.Copy(res, req.Body); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	settings, err := client.Signature.UpdateNotificationSettings(context.Background(), &NotificationSettings{SmsSender: "Sender"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Sender", settings.SmsSender)
+}
+
+func TestSignatureService_PreviewNotification(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/signature/notification-settings/preview", req.URL.Path)
+
+		var body NotificationPreviewRequest
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		assert.Equal(t, NotificationTypeReminder, body.NotificationType)
+		assert.Equal(t, "Contract", body.MergeFields[MergeFieldDocumentTitle])
+
+		if _, err := io.WriteString(res, `{"sms":{"language":"NO","text":"Please sign Contract"}}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	preview, err := client.Signature.PreviewNotification(context.Background(), &NotificationPreviewRequest{
+		NotificationType: NotificationTypeReminder,
+		Language:         LanguageNorwegian,
+		MergeFields:      map[string]string{MergeFieldDocumentTitle: "Contract"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Please sign Contract", preview.Sms.Text)
+}
+
+func TestSignatureService_ResendNotification(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/signature/documents/someDocumentId/signers/someSignerId/notify", req.URL.Path)
+
+		var body ResendNotificationRequest
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		assert.Equal(t, NotificationTypeReminder, body.NotificationType)
+		assert.Equal(t, NotificationSetupSendSms, body.Setup)
+		assert.Equal(t, "Please sign", body.Sms.Text)
+	})
+
+	err := client.Signature.ResendNotification(context.Background(), "someDocumentId", "someSignerId", &ResendNotificationRequest{
+		NotificationType: NotificationTypeReminder,
+		Setup:            NotificationSetupSendSms,
+		Sms:              &Sms{Language: LanguageEnglish, Text: "Please sign"},
+	})
+	assert.NoError(t, err)
+
+	err = client.Signature.ResendNotification(context.Background(), "someDocumentId", "someSignerId", &ResendNotificationRequest{
+		NotificationType: NotificationTypeFinalReceipt,
+	})
+	assert.Error(t, err)
+
+	err = client.Signature.ResendNotification(context.Background(), "someDocumentId", "someSignerId", &ResendNotificationRequest{
+		NotificationType: NotificationTypeSignRequest,
+		Setup:            NotificationSetupOff,
+	})
+	assert.Error(t, err)
+
+	err = client.Signature.ResendNotification(context.Background(), "someDocumentId", "someSignerId", nil)
+	assert.EqualError(t, err, "no resend notification request")
+}
+
+func TestSignatureService_ListNotifications(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, "/signature/documents/someDocumentId/signers/someSignerId/notifications", req.URL.Path)
+		if _, err := io.WriteString(res, `[{"notificationType":"signRequest","channel":"email","status":"delivered"},{"notificationType":"reminder","channel":"sms","status":"failed","error":"unknown number"}]`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	entries, err := client.Signature.ListNotifications(context.Background(), "someDocumentId", "someSignerId")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, NotificationChannelSms, entries[1].Channel)
+	assert.Equal(t, NotificationStatusFailed, entries[1].Status)
+}
+
+func TestNotificationTemplates(t *testing.T) {
+	templates := NotificationTemplatesFrom(&Notification{
+		SignRequest: &SignRequest{
+			Email: []*Email{{Language: LanguageEnglish, Subject: "Sign {document-title}", Text: "Sign here: {url}"}},
+		},
+	})
+	templates.SetSms(NotificationTypeReminder, &Sms{Language: LanguageNorwegian, Text: "Husk {document-title} innen {deadline}"})
+	templates.SetSms(NotificationTypeReminder, &Sms{Language: LanguageNorwegian, Text: "Signer {document-title}"})
+
+	notification := templates.Notification()
+	assert.Len(t, notification.SignRequest.Email, 1)
+	assert.Len(t, notification.Reminder.Sms, 1)
+	assert.Equal(t, "Signer {document-title}", notification.Reminder.Sms[0].Text)
+	assert.Nil(t, notification.FinalReceipt)
+
+	values := map[string]string{MergeFieldDocumentTitle: "Contract", MergeFieldURL: "https://example.com"}
+
+	preview := templates.Preview(NotificationTypeSignRequest, LanguageEnglish, values)
+	assert.Equal(t, "Sign Contract", preview.Email.Subject)
+	assert.Equal(t, "Sign here: https://example.com", preview.Email.Text)
+	assert.Nil(t, preview.Sms)
+
+	preview = templates.Preview(NotificationTypeReminder, LanguageNorwegian, values)
+	assert.Nil(t, preview.Email)
+	assert.Equal(t, "Signer Contract", preview.Sms.Text)
+}
+
+func TestRenderMergeFields(t *testing.T) {
+	assert.Equal(t, "Contract by {deadline}", RenderMergeFields("{document-title} by {deadline}", map[string]string{
+		MergeFieldDocumentTitle: "Contract",
+	}))
+	assert.Equal(t, "{document-title}", RenderMergeFields("{document-title}", nil))
+}
@@ -0,0 +1,202 @@
+// Package orgno validates and normalises the organization numbers of the Nordic countries: the Norwegian organisasjonsnummer,
+// the Swedish organisationsnummer, the Danish CVR number and the Finnish business ID (Y-tunnus).
+package orgno
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/larwef/signicat/internal/checkdigit"
+)
+
+// Supported countries, as ISO 3166-1 alpha-2 codes, as used in signicat.OrganizationInfo.CountryCode.
+const (
+	Norway  = "NO"
+	Sweden  = "SE"
+	Denmark = "DK"
+	Finland = "FI"
+)
+
+// Kinds of numbers.
+const (
+	// KindOrganization is a number given by the register of the country.
+	KindOrganization = "organization"
+	// KindPersonal is the personnummer of a Swedish sole trader, which is its organisationsnummer.
+	KindPersonal = "personal"
+)
+
+// ErrUnsupportedCountry is returned for countries this package doesn't know the organization numbers of.
+var ErrUnsupportedCountry = errors.New("unsupported country")
+
+// InvalidError tells why an organization number is invalid.
+type InvalidError struct {
+	Country string
+	Reason  string
+}
+
+func (e *InvalidError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", numberNames[e.Country], e.Reason)
+}
+
+var numberNames = map[string]string{
+	Norway:  "organisasjonsnummer",
+	Sweden:  "organisationsnummer",
+	Denmark: "CVR number",
+	Finland: "Y-tunnus",
+}
+
+var normalisers = map[string]func(s string) (string, error){
+	Norway:  normaliseNorwegian,
+	Sweden:  normaliseSwedish,
+	Denmark: normaliseDanish,
+	Finland: normaliseFinnish,
+}
+
+// Normalise validates the organization number of a country, and returns it in its canonical form:
+//
+//	NO  9 digits, eg. 974760673
+//	SE  10 digits with a hyphen, eg. 556016-0680
+//	DK  8 digits, eg. 24256790
+//	FI  7 digits, a hyphen and the check digit, eg. 0112038-9
+//
+// Spaces are ignored. Returns an *InvalidError if the number is invalid, and ErrUnsupportedCountry if the country isn't one of
+// the supported.
+func Normalise(country, s string) (string, error) {
+	normalise, ok := normalisers[strings.ToUpper(country)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedCountry, country)
+	}
+
+	return normalise(strings.Join(strings.Fields(s), ""))
+}
+
+// Validate checks the organization number of a country. See Normalise.
+func Validate(country, s string) error {
+	_, err := Normalise(country, s)
+	return err
+}
+
+// Kind validates the organization number of a country, and returns its kind, KindPersonal for the personnummer of a Swedish
+// sole trader and KindOrganization for every other number. See Normalise.
+func Kind(country, s string) (string, error) {
+	normalised, err := Normalise(country, s)
+	if err != nil {
+		return "", err
+	}
+	// The third digit of a personnummer is the first digit of the month, the third digit of other numbers is at least 2.
+	if strings.EqualFold(country, Sweden) && normalised[2] < '2' {
+		return KindPersonal, nil
+	}
+
+	return KindOrganization, nil
+}
+
+// Supported reports whether there is validation for the organization numbers of a country.
+func Supported(country string) bool {
+	_, ok := normalisers[strings.ToUpper(country)]
+	return ok
+}
+
+// normaliseNorwegian checks the modulus 11 check digit of a 9 digit number.
+func normaliseNorwegian(s string) (string, error) {
+	invalid := func(reason string) error {
+		return &InvalidError{Country: Norway, Reason: reason}
+	}
+
+	d, ok := checkdigit.Digits(s)
+	if !ok || len(d) != 9 {
+		return "", invalid("must be 9 digits")
+	}
+	if d[0] != 8 && d[0] != 9 {
+		return "", invalid("must start with 8 or 9")
+	}
+
+	k := 11 - checkdigit.WeightedSum(d, []int{3, 2, 7, 6, 5, 4, 3, 2})%11
+	if k == 11 {
+		k = 0
+	}
+	if k != d[8] {
+		return "", invalid("check digit is wrong")
+	}
+
+	return s, nil
+}
+
+// normaliseSwedish checks the Luhn check digit of a 10 digit number, optionally with a hyphen before the last four digits, or
+// with the prefix 16 some registers use. Sole traders use their personnummer, which is accepted too.
+func normaliseSwedish(s string) (string, error) {
+	invalid := func(reason string) error {
+		return &InvalidError{Country: Sweden, Reason: reason}
+	}
+
+	if i := strings.IndexByte(s, '-'); i >= 0 && i == len(s)-5 {
+		s = s[:i] + s[i+1:]
+	}
+	if len(s) == 12 && strings.HasPrefix(s, "16") {
+		s = s[2:]
+	}
+	d, ok := checkdigit.Digits(s)
+	if !ok || len(d) != 10 {
+		return "", invalid("must be 10 digits")
+	}
+	if !checkdigit.Luhn(d) {
+		return "", invalid("check digit is wrong")
+	}
+
+	return s[:6] + "-" + s[6:], nil
+}
+
+// normaliseDanish checks the modulus 11 of an 8 digit number, optionally prefixed with DK as in the VAT number.
+func normaliseDanish(s string) (string, error) {
+	invalid := func(reason string) error {
+		return &InvalidError{Country: Denmark, Reason: reason}
+	}
+
+	s = strings.TrimPrefix(strings.ToUpper(s), "DK")
+	d, ok := checkdigit.Digits(s)
+	if !ok || len(d) != 8 {
+		return "", invalid("must be 8 digits")
+	}
+	if d[0] == 0 {
+		return "", invalid("can't start with 0")
+	}
+	if checkdigit.WeightedSum(d, []int{2, 7, 6, 5, 4, 3, 2, 1})%11 != 0 {
+		return "", invalid("check digit is wrong")
+	}
+
+	return s, nil
+}
+
+// normaliseFinnish checks the modulus 11 check digit of a Y-tunnus, 7 digits, a hyphen and the check digit. Old numbers with 6
+// digits are padded with a leading zero.
+func normaliseFinnish(s string) (string, error) {
+	invalid := func(reason string) error {
+		return &InvalidError{Country: Finland, Reason: reason}
+	}
+
+	if i := strings.IndexByte(s, '-'); i >= 0 && i == len(s)-2 {
+		s = s[:i] + s[i+1:]
+	}
+	if len(s) == 7 {
+		s = "0" + s
+	}
+	d, ok := checkdigit.Digits(s)
+	if !ok || len(d) != 8 {
+		return "", invalid("must be 7 digits, a hyphen and the check digit")
+	}
+
+	r := checkdigit.WeightedSum(d, []int{7, 9, 10, 5, 8, 4, 2}) % 11
+	if r == 1 {
+		return "", invalid("the digits have no valid check digit")
+	}
+	k := 0
+	if r > 1 {
+		k = 11 - r
+	}
+	if k != d[7] {
+		return "", invalid("check digit is wrong")
+	}
+
+	return s[:7] + "-" + s[7:], nil
+}
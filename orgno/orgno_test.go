@@ -0,0 +1,96 @@
+package orgno
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalise(t *testing.T) {
+	tests := []struct {
+		name    string
+		country string
+		number  string
+		want    string
+		err     string
+	}{
+		{name: "NO with spaces", country: Norway, number: "974 760 673", want: "974760673"},
+		{name: "NO", country: Norway, number: "923609016", want: "923609016"},
+		{name: "NO wrong check digit", country: Norway, number: "974760674", err: "check digit is wrong"},
+		{name: "NO too short", country: Norway, number: "97476067", err: "must be 9 digits"},
+		{name: "NO first digit", country: Norway, number: "123456785", err: "must start with 8 or 9"},
+
+		{name: "SE", country: Sweden, number: "556016-0680", want: "556016-0680"},
+		{name: "SE without hyphen", country: Sweden, number: "5560160680", want: "556016-0680"},
+		{name: "SE with prefix 16", country: Sweden, number: "165560160680", want: "556016-0680"},
+		{name: "SE lower case country", country: "se", number: "556016-0680", want: "556016-0680"},
+		{name: "SE wrong check digit", country: Sweden, number: "556016-0681", err: "check digit is wrong"},
+		{name: "SE too short", country: Sweden, number: "556016", err: "must be 10 digits"},
+		{name: "SE personnummer", country: Sweden, number: "811228-9874", want: "811228-9874"},
+		{name: "SE personnummer wrong check digit", country: Sweden, number: "811228-9875", err: "check digit is wrong"},
+
+		{name: "DK", country: Denmark, number: "24256790", want: "24256790"},
+		{name: "DK VAT number", country: Denmark, number: "DK 10 15 08 17", want: "10150817"},
+		{name: "DK wrong check digit", country: Denmark, number: "24256791", err: "check digit is wrong"},
+		{name: "DK leading zero", country: Denmark, number: "04256790", err: "can't start with 0"},
+
+		{name: "FI", country: Finland, number: "0112038-9", want: "0112038-9"},
+		{name: "FI 6 digits", country: Finland, number: "112038-9", want: "0112038-9"},
+		{name: "FI without hyphen", country: Finland, number: "15728600", want: "1572860-0"},
+		{name: "FI wrong check digit", country: Finland, number: "0112038-8", err: "check digit is wrong"},
+		{name: "FI letter", country: Finland, number: "0112038-X", err: "must be 7 digits, a hyphen and the check digit"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			normalised, err := Normalise(test.country, test.number)
+			if test.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, normalised)
+				return
+			}
+
+			var invalidErr *InvalidError
+			assert.True(t, errors.As(err, &invalidErr))
+			assert.Equal(t, test.err, invalidErr.Reason)
+			assert.Equal(t, "invalid "+numberNames[invalidErr.Country]+": "+test.err, err.Error())
+		})
+	}
+}
+
+func TestKind(t *testing.T) {
+	tests := []struct {
+		country string
+		number  string
+		want    string
+	}{
+		{Norway, "974760673", KindOrganization},
+		{Sweden, "556016-0680", KindOrganization},
+		{Sweden, "811228-9874", KindPersonal},
+		{"se", "8112289874", KindPersonal},
+		{Denmark, "24256790", KindOrganization},
+		{Finland, "0112038-9", KindOrganization},
+	}
+
+	for _, test := range tests {
+		kind, err := Kind(test.country, test.number)
+		assert.NoError(t, err, test.number)
+		assert.Equal(t, test.want, kind, test.number)
+	}
+
+	_, err := Kind(Sweden, "811228-9875")
+	var invalidErr *InvalidError
+	assert.True(t, errors.As(err, &invalidErr))
+}
+
+func TestSupported(t *testing.T) {
+	for _, country := range []string{Norway, Sweden, Denmark, Finland, "dk"} {
+		assert.True(t, Supported(country), country)
+	}
+	assert.False(t, Supported("DE"))
+
+	_, err := Normalise("DE", "123456789")
+	assert.True(t, errors.Is(err, ErrUnsupportedCountry))
+	assert.EqualError(t, err, "unsupported country: DE")
+}
@@ -0,0 +1,471 @@
+package preflight
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// PDFInfo is what preflight found in the structure of a PDF.
+type PDFInfo struct {
+	// Version is the version in the header, eg. 1.7.
+	Version string
+	Pages   int
+	// Encrypted is set if the PDF has an encryption dictionary. Permissions is then set if it could be read.
+	Encrypted   bool
+	Permissions *Permissions
+	JavaScript  bool
+	// Signatures is the number of signature dictionaries, ie. signatures which were already added to the PDF.
+	Signatures       int
+	EmbeddedFonts    []string
+	NonEmbeddedFonts []string
+	// PDFA is the PDF/A part and conformance level claimed in the XMP metadata, eg. 2B. Empty if none is claimed.
+	PDFA string
+}
+
+// Permissions is what the user of an encrypted PDF is allowed to do.
+type Permissions struct {
+	Print     bool
+	Modify    bool
+	Copy      bool
+	Annotate  bool
+	FillForms bool
+}
+
+var (
+	headerRegexp     = regexp.MustCompile(`%PDF-(\d\.\d)`)
+	objRegexp        = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+	lengthRegexp     = regexp.MustCompile(`/Length\s+(\d+)(?:\s*[/>])`)
+	encryptRegexp    = regexp.MustCompile(`/Encrypt\s*(?:(\d+)\s+\d+\s+R|<<)`)
+	permissionRegexp = regexp.MustCompile(`/P\s+(-?\d+)`)
+	pageRegexp       = regexp.MustCompile(`/Type\s*/Page(?:[^A-Za-z0-9]|$)`)
+	objStmRegexp     = regexp.MustCompile(`/Type\s*/ObjStm\b`)
+	metadataRegexp   = regexp.MustCompile(`/Type\s*/Metadata\b`)
+	flateRegexp      = regexp.MustCompile(`/Filter\s*(?:\[\s*)?/FlateDecode\s*(?:\])?\s*[/>]`)
+	javaScriptRegexp = regexp.MustCompile(`/(?:JavaScript|JS)\b`)
+	signatureRegexp  = regexp.MustCompile(`/ByteRange\s*\[`)
+	descriptorRegexp = regexp.MustCompile(`/Type\s*/FontDescriptor\b`)
+	fontFileRegexp   = regexp.MustCompile(`/FontFile[23]?\b`)
+	fontNameRegexp   = regexp.MustCompile(`/(?:FontName|BaseFont)\s*/([^\s/<>\[\]()]+)`)
+	type1FontRegexp  = regexp.MustCompile(`/Type\s*/Font\b[\s\S]*?/Subtype\s*/Type1\b|/Subtype\s*/Type1\b[\s\S]*?/Type\s*/Font\b`)
+	pdfaPartRegexp   = regexp.MustCompile(`pdfaid:part(?:\s*=\s*["']|>)\s*(\d)`)
+	pdfaLevelRegexp  = regexp.MustCompile(`pdfaid:conformance(?:\s*=\s*["']|>)\s*([A-Za-z])`)
+	nRegexp          = regexp.MustCompile(`/N\s+(\d+)`)
+	firstRegexp      = regexp.MustCompile(`/First\s+(\d+)`)
+)
+
+// object is an indirect object. Stream is nil if the object isn't a stream.
+type object struct {
+	dict   []byte
+	stream []byte
+}
+
+// errStreamTooLarge is returned when a stream decompresses to more than Options.MaxStreamSize.
+var errStreamTooLarge = errors.New("decompressed stream is too large")
+
+// checkPDF parses the structure of data and adds the issues found to report.
+func checkPDF(data []byte, opts *Options, report *Report) {
+	info := &PDFInfo{}
+	report.PDF = info
+
+	maxStreamSize := opts.MaxStreamSize
+	if maxStreamSize == 0 {
+		maxStreamSize = DefaultMaxStreamSize
+	}
+
+	header := data
+	if len(header) > 1024 {
+		header = header[:1024]
+	}
+	if m := headerRegexp.FindSubmatch(header); m != nil {
+		info.Version = string(m[1])
+	} else {
+		report.add(SeverityError, CodeCorrupt, "missing PDF header")
+	}
+
+	trailer := data
+	if len(trailer) > 1024 {
+		trailer = trailer[len(trailer)-1024:]
+	}
+	if !bytes.Contains(trailer, []byte("%%EOF")) {
+		report.add(SeverityError, CodeCorrupt, "missing %%EOF marker, the file may be truncated")
+	}
+	if !bytes.Contains(data, []byte("startxref")) {
+		report.add(SeverityError, CodeCorrupt, "missing cross-reference table")
+	}
+
+	objects := parseObjects(data)
+	if len(objects) == 0 {
+		report.add(SeverityError, CodeCorrupt, "no objects found")
+		return
+	}
+
+	// The trailer and cross-reference streams are never compressed, so the raw bytes are searched for the encryption dictionary.
+	if m := encryptRegexp.FindSubmatch(data); m != nil {
+		info.Encrypted = true
+		if len(m[1]) > 0 {
+			num, _ := strconv.Atoi(string(m[1]))
+			if o, ok := objects[num]; ok {
+				info.Permissions = parsePermissions(o.dict)
+			}
+		}
+		report.add(SeverityError, CodeEncrypted, "the PDF is encrypted")
+	}
+
+	// Objects in object streams can't be read when the PDF is encrypted.
+	if !info.Encrypted {
+		if err := expandObjectStreams(objects, maxStreamSize); errors.Is(err, errStreamTooLarge) {
+			report.add(SeverityError, CodeStreamTooLarge, err.Error())
+		} else if err != nil {
+			report.add(SeverityError, CodeCorrupt, err.Error())
+		}
+	}
+
+	embedded, nonEmbedded := map[string]bool{}, map[string]bool{}
+	for _, num := range sortedKeys(objects) {
+		o := objects[num]
+
+		if pageRegexp.Match(o.dict) {
+			info.Pages++
+		}
+		if javaScriptRegexp.Match(o.dict) {
+			info.JavaScript = true
+		}
+		if signatureRegexp.Match(o.dict) {
+			info.Signatures++
+		}
+
+		switch {
+		case descriptorRegexp.Match(o.dict):
+			name := fontName(o.dict, num)
+			if fontFileRegexp.Match(o.dict) {
+				embedded[name] = true
+			} else {
+				nonEmbedded[name] = true
+			}
+		case type1FontRegexp.Match(o.dict) && !bytes.Contains(o.dict, []byte("/FontDescriptor")):
+			// The standard 14 fonts have no font descriptor, and are never embedded.
+			nonEmbedded[fontName(o.dict, num)] = true
+		}
+
+		if metadataRegexp.Match(o.dict) && o.stream != nil && info.PDFA == "" && !info.Encrypted {
+			xmp, err := decodeStream(o, maxStreamSize)
+			if err == nil {
+				info.PDFA = pdfaConformance(xmp)
+			} else if errors.Is(err, errStreamTooLarge) {
+				report.add(SeverityError, CodeStreamTooLarge, fmt.Sprintf("metadata stream %d: %v", num, err))
+			}
+		}
+	}
+	info.EmbeddedFonts = keys(embedded)
+	info.NonEmbeddedFonts = keys(nonEmbedded)
+
+	if info.Pages == 0 && !info.Encrypted {
+		report.add(SeverityError, CodeCorrupt, "no pages found")
+	}
+	if opts.MaxPages > 0 && info.Pages > opts.MaxPages {
+		report.add(SeverityError, CodeTooManyPages, fmt.Sprintf("%d pages is more than the limit of %d", info.Pages, opts.MaxPages))
+	}
+	if info.JavaScript {
+		report.add(SeverityError, CodeJavaScript, "the PDF contains JavaScript")
+	}
+	if info.Signatures > 0 {
+		report.add(SeverityWarning, CodeSigned, fmt.Sprintf("the PDF already has %d signature(s), which may be invalidated", info.Signatures))
+	}
+	if len(info.NonEmbeddedFonts) > 0 {
+		severity := SeverityWarning
+		if opts.RequireEmbeddedFonts {
+			severity = SeverityError
+		}
+		report.add(severity, CodeFontsNotEmbedded, fmt.Sprintf("fonts not embedded: %v", info.NonEmbeddedFonts))
+	}
+	if info.PDFA == "" {
+		severity := SeverityWarning
+		if opts.RequirePDFA {
+			severity = SeverityError
+		}
+		report.add(severity, CodeNotPDFA, "the PDF doesn't claim PDF/A conformance")
+	}
+}
+
+// parseObjects finds the indirect objects in data. Objects defined more than once, by incremental updates, keep the last
+// definition.
+func parseObjects(data []byte) map[int]*object {
+	objects := make(map[int]*object)
+
+	pos := 0
+	for {
+		loc := objRegexp.FindSubmatchIndex(data[pos:])
+		if loc == nil {
+			return objects
+		}
+		num, _ := strconv.Atoi(string(data[pos+loc[2] : pos+loc[3]]))
+		start := pos + loc[1]
+
+		o, end := parseObject(data, start)
+		objects[num] = o
+		pos = end
+	}
+}
+
+// parseObject parses the object starting at start, and returns it with the position after it.
+func parseObject(data []byte, start int) (*object, int) {
+	i := skipSpace(data, start)
+	dictEnd := -1
+	if bytes.HasPrefix(data[i:], []byte("<<")) {
+		dictEnd = skipDict(data, i)
+	}
+
+	if dictEnd > 0 {
+		o := &object{dict: data[i:dictEnd]}
+		j := skipSpace(data, dictEnd)
+		if bytes.HasPrefix(data[j:], []byte("stream")) {
+			j += len("stream")
+			if bytes.HasPrefix(data[j:], []byte("\r\n")) {
+				j += 2
+			} else if j < len(data) && data[j] == '\n' {
+				j++
+			}
+
+			// Use the length if it is direct, otherwise look for the end of the stream.
+			end := -1
+			if m := lengthRegexp.FindSubmatch(o.dict); m != nil {
+				if n, err := strconv.Atoi(string(m[1])); err == nil && j+n <= len(data) {
+					end = j + n
+				}
+			}
+			if end >= 0 && bytes.Contains(data[end:minInt(end+32, len(data))], []byte("endstream")) {
+				o.stream = data[j:end]
+			} else {
+				end = j + bytes.Index(data[j:], []byte("endstream"))
+				if end < j {
+					end = len(data)
+				}
+				o.stream = bytes.TrimRight(data[j:end], "\r\n")
+			}
+			dictEnd = end
+		}
+
+		return o, objectEnd(data, dictEnd)
+	}
+
+	end := objectEnd(data, i)
+	return &object{dict: bytes.TrimSuffix(data[i:end], []byte("endobj"))}, end
+}
+
+// objectEnd returns the position after the endobj keyword following i.
+func objectEnd(data []byte, i int) int {
+	if k := bytes.Index(data[i:], []byte("endobj")); k >= 0 {
+		return i + k + len("endobj")
+	}
+	return len(data)
+}
+
+// skipDict returns the position after the dictionary starting at i, or -1 if it doesn't end.
+func skipDict(data []byte, i int) int {
+	depth := 0
+	for i < len(data) {
+		switch {
+		case data[i] == '(':
+			i = skipString(data, i)
+			continue
+		case bytes.HasPrefix(data[i:], []byte("<<")):
+			depth++
+			i += 2
+			continue
+		case bytes.HasPrefix(data[i:], []byte(">>")):
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+			continue
+		}
+		i++
+	}
+
+	return -1
+}
+
+// skipString returns the position after the literal string starting at i.
+func skipString(data []byte, i int) int {
+	depth := 0
+	for ; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+
+	return i
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && bytes.IndexByte([]byte(" \t\r\n\f\x00"), data[i]) >= 0 {
+		i++
+	}
+	return i
+}
+
+// expandObjectStreams adds the objects stored in object streams. Objects defined directly take precedence.
+func expandObjectStreams(objects map[int]*object, maxStreamSize int64) error {
+	for _, num := range sortedKeys(objects) {
+		o := objects[num]
+		if o.stream == nil || !objStmRegexp.Match(o.dict) {
+			continue
+		}
+
+		content, err := decodeStream(o, maxStreamSize)
+		if err != nil {
+			return fmt.Errorf("object stream %d can't be decoded: %w", num, err)
+		}
+
+		n, first := intValue(nRegexp, o.dict), intValue(firstRegexp, o.dict)
+		if n < 0 || first < 0 {
+			return fmt.Errorf("object stream %d has an invalid header", num)
+		}
+		if first > len(content) {
+			return fmt.Errorf("object stream %d is truncated", num)
+		}
+		// The offsets are compared to the length of the objects after first, so they can't overflow.
+		fields, objectsLen := bytes.Fields(content[:first]), len(content)-first
+		if n > len(fields)/2 {
+			return fmt.Errorf("object stream %d has an invalid header", num)
+		}
+
+		for i := 0; i < n; i++ {
+			objNum, err1 := strconv.Atoi(string(fields[2*i]))
+			offset, err2 := strconv.Atoi(string(fields[2*i+1]))
+			if err1 != nil || err2 != nil || offset < 0 || offset > objectsLen {
+				return fmt.Errorf("object stream %d has an invalid header", num)
+			}
+
+			end := len(content)
+			if i+1 < n {
+				if next, err := strconv.Atoi(string(fields[2*i+3])); err == nil && next >= offset && next <= objectsLen {
+					end = first + next
+				}
+			}
+			if _, ok := objects[objNum]; !ok {
+				objects[objNum] = &object{dict: content[first+offset : end]}
+			}
+		}
+	}
+
+	return nil
+}
+
+// decodeStream returns the content of a stream which is either uncompressed or compressed with FlateDecode. Other filters are
+// returned as is. errStreamTooLarge is returned if the content is larger than maxSize.
+func decodeStream(o *object, maxSize int64) ([]byte, error) {
+	if !flateRegexp.Match(o.dict) {
+		return o.stream, nil
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(o.stream))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	content, err := ioutil.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > maxSize {
+		return nil, fmt.Errorf("%w, the limit is %d bytes", errStreamTooLarge, maxSize)
+	}
+
+	return content, nil
+}
+
+func parsePermissions(dict []byte) *Permissions {
+	m := permissionRegexp.FindSubmatch(dict)
+	if m == nil {
+		return nil
+	}
+	p, err := strconv.ParseInt(string(m[1]), 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	// The bit positions are defined in table 22 of ISO 32000-1.
+	return &Permissions{
+		Print:     p&(1<<2) != 0,
+		Modify:    p&(1<<3) != 0,
+		Copy:      p&(1<<4) != 0,
+		Annotate:  p&(1<<5) != 0,
+		FillForms: p&(1<<8) != 0,
+	}
+}
+
+func pdfaConformance(xmp []byte) string {
+	part := pdfaPartRegexp.FindSubmatch(xmp)
+	if part == nil {
+		return ""
+	}
+
+	level := pdfaLevelRegexp.FindSubmatch(xmp)
+	if level == nil {
+		return string(part[1])
+	}
+
+	return string(part[1]) + string(bytes.ToUpper(level[1]))
+}
+
+func fontName(dict []byte, num int) string {
+	if m := fontNameRegexp.FindSubmatch(dict); m != nil {
+		return string(m[1])
+	}
+	return fmt.Sprintf("object %d", num)
+}
+
+func intValue(re *regexp.Regexp, dict []byte) int {
+	m := re.FindSubmatch(dict)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(string(m[1]))
+	return n
+}
+
+func sortedKeys(objects map[int]*object) []int {
+	nums := make([]int, 0, len(objects))
+	for num := range objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	return nums
+}
+
+func keys(m map[string]bool) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	s := make([]string, 0, len(m))
+	for k := range m {
+		s = append(s, k)
+	}
+	sort.Strings(s)
+	return s
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,161 @@
+// Package preflight checks files locally before they are sent to Signicat for signing, to catch the files which would be rejected
+// or turn out badly, eg. encrypted, corrupt or too large PDFs, or PDFs with JavaScript.
+//
+// PDFs are checked by scanning their objects, including the objects in compressed object streams. It is not a full PDF parser,
+// and a clean report doesn't guarantee Signicat accepts the file.
+package preflight
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/larwef/signicat/internal/sniff"
+)
+
+// DefaultMaxSize is the largest file accepted when Options.MaxSize is 0.
+const DefaultMaxSize = 50 << 20
+
+// DefaultMaxStreamSize is the largest decompressed PDF stream read when Options.MaxStreamSize is 0.
+const DefaultMaxStreamSize = 64 << 20
+
+// Severity tells whether an issue stops the file from being sent.
+type Severity int
+
+// Available severities.
+const (
+	// SeverityWarning is an issue which likely gives a poor result, but doesn't stop the file from being signed.
+	SeverityWarning Severity = iota
+	// SeverityError is an issue which makes Signicat reject the file.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Available issue codes.
+const (
+	CodeEmpty            = "empty"
+	CodeTooLarge         = "too_large"
+	CodeUnsupportedType  = "unsupported_type"
+	CodeCorrupt          = "corrupt"
+	CodeEncrypted        = "encrypted"
+	CodeTooManyPages     = "too_many_pages"
+	CodeJavaScript       = "javascript"
+	CodeSigned           = "signed"
+	CodeFontsNotEmbedded = "fonts_not_embedded"
+	CodeNotPDFA          = "not_pdfa"
+	CodeStreamTooLarge   = "stream_too_large"
+)
+
+// Options configures the checks. The zero value is usable.
+type Options struct {
+	// MaxSize is the largest file accepted, in bytes. DefaultMaxSize is used if 0.
+	MaxSize int64
+	// MaxStreamSize is the largest a compressed PDF stream is decompressed to, in bytes, so a small file can't expand to
+	// gigabytes while it is checked. DefaultMaxStreamSize is used if 0.
+	MaxStreamSize int64
+	// MaxPages is the largest number of pages accepted in a PDF. There is no limit if 0.
+	MaxPages int
+	// RequireEmbeddedFonts makes fonts which are not embedded an error instead of a warning.
+	RequireEmbeddedFonts bool
+	// RequirePDFA makes a PDF which doesn't claim PDF/A conformance an error instead of a warning.
+	RequirePDFA bool
+}
+
+// Issue is a problem found with a file.
+type Issue struct {
+	Severity Severity
+	// Code is one of the Code constants.
+	Code    string
+	Message string
+}
+
+func (i *Issue) Error() string {
+	return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+}
+
+// Issues are the problems found with a file.
+type Issues []*Issue
+
+func (e Issues) Error() string {
+	msgs := make([]string, len(e))
+	for i, issue := range e {
+		msgs[i] = issue.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Report is the result of checking a file.
+type Report struct {
+	// ContentType is the detected content type, eg. application/pdf.
+	ContentType string
+	Size        int64
+	// PDF is set if the file is a PDF.
+	PDF    *PDFInfo
+	Issues Issues
+}
+
+// Errors returns the issues with SeverityError.
+func (r *Report) Errors() Issues {
+	var errs Issues
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			errs = append(errs, issue)
+		}
+	}
+
+	return errs
+}
+
+// Err returns the issues with SeverityError as an error, or nil if there are none.
+func (r *Report) Err() error {
+	if errs := r.Errors(); len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (r *Report) add(severity Severity, code, message string) {
+	r.Issues = append(r.Issues, &Issue{Severity: severity, Code: code, Message: message})
+}
+
+// Check runs the checks on the content of a file, ie. the decoded DataToSign.Base64Content. The file name is only used to detect
+// the content type. opts can be nil.
+func Check(data []byte, fileName string, opts *Options) *Report {
+	if opts == nil {
+		opts = &Options{}
+	}
+	maxSize := opts.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxSize
+	}
+
+	report := &Report{
+		ContentType: sniff.ContentType(data, fileName),
+		Size:        int64(len(data)),
+	}
+
+	if len(data) == 0 {
+		report.add(SeverityError, CodeEmpty, "the file is empty")
+		return report
+	}
+	if report.Size > maxSize {
+		report.add(SeverityError, CodeTooLarge, fmt.Sprintf("%d bytes is more than the limit of %d", report.Size, maxSize))
+	}
+
+	switch report.ContentType {
+	case sniff.PDF:
+		checkPDF(data, opts, report)
+	case sniff.DOCX, sniff.XML, sniff.Text:
+	default:
+		report.add(SeverityError, CodeUnsupportedType, "the file is not a PDF, DOCX, XML or text file")
+	}
+
+	return report
+}
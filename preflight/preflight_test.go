@@ -0,0 +1,209 @@
+package preflight
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	catalog    = "<< /Type /Catalog /Pages 2 0 R >>"
+	pages      = "<< /Type /Pages /Kids [3 0 R] /Count 1 >>"
+	page       = "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 4 0 R >> >> >>"
+	font       = "<< /Type /Font /Subtype /TrueType /BaseFont /ABCDEF+Arial /FontDescriptor 5 0 R >>"
+	descriptor = "<< /Type /FontDescriptor /FontName /ABCDEF+Arial /FontFile2 6 0 R >>"
+	helvetica  = "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"
+	xmp        = `<x:xmpmeta><rdf:Description xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/" pdfaid:part="2" pdfaid:conformance="B"/></x:xmpmeta>`
+)
+
+// buildPDF writes a PDF with the objects numbered from 1. Objects starting with "stream:" are written as streams, with the
+// dictionary and the content separated by a newline.
+func buildPDF(trailer string, objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(objects))
+	for i, o := range objects {
+		offsets[i] = buf.Len()
+		if bytes.HasPrefix([]byte(o), []byte("stream:")) {
+			parts := bytes.SplitN([]byte(o[len("stream:"):]), []byte("\n"), 2)
+			dict := bytes.Replace(parts[0], []byte(">>"), []byte(fmt.Sprintf(" /Length %d >>", len(parts[1]))), 1)
+			fmt.Fprintf(&buf, "%d 0 obj\n%s\nstream\n%s\nendstream\nendobj\n", i+1, dict, parts[1])
+			continue
+		}
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R %s >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, trailer, xref)
+
+	return buf.Bytes()
+}
+
+func deflate(s string) string {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write([]byte(s))
+	w.Close()
+	return buf.String()
+}
+
+func codes(issues Issues) []string {
+	var c []string
+	for _, issue := range issues {
+		c = append(c, issue.Code)
+	}
+	return c
+}
+
+func TestCheck_PDF(t *testing.T) {
+	pdf := buildPDF("", catalog, pages, page, font, descriptor, "stream:<< >>\nfontdata",
+		"stream:<< /Type /Metadata /Subtype /XML /Filter /FlateDecode >>\n"+deflate(xmp))
+
+	report := Check(pdf, "contract.pdf", nil)
+	assert.Empty(t, report.Issues)
+	assert.Equal(t, "application/pdf", report.ContentType)
+	assert.Equal(t, "1.7", report.PDF.Version)
+	assert.Equal(t, 1, report.PDF.Pages)
+	assert.Equal(t, []string{"ABCDEF+Arial"}, report.PDF.EmbeddedFonts)
+	assert.Equal(t, "2B", report.PDF.PDFA)
+	assert.NoError(t, report.Err())
+}
+
+func TestCheck_PDFIssues(t *testing.T) {
+	tests := []struct {
+		name     string
+		pdf      []byte
+		opts     *Options
+		expected []string
+	}{
+		{
+			name:     "not embedded fonts and not pdfa",
+			pdf:      buildPDF("", catalog, pages, page, helvetica),
+			expected: []string{CodeFontsNotEmbedded, CodeNotPDFA},
+		},
+		{
+			name:     "required embedded fonts",
+			pdf:      buildPDF("", catalog, pages, page, helvetica),
+			opts:     &Options{RequireEmbeddedFonts: true, RequirePDFA: true},
+			expected: []string{CodeFontsNotEmbedded, CodeNotPDFA},
+		},
+		{
+			name:     "javascript",
+			pdf:      buildPDF("", "<< /Type /Catalog /Pages 2 0 R /OpenAction << /S /JavaScript /JS (app.alert\\(1\\)) >> >>", pages, page),
+			expected: []string{CodeJavaScript, CodeNotPDFA},
+		},
+		{
+			name:     "signed",
+			pdf:      buildPDF("", catalog, pages, page, "<< /Type /Sig /Filter /Adobe.PPKLite /ByteRange [0 10 20 30] /Contents <00> >>"),
+			expected: []string{CodeSigned, CodeNotPDFA},
+		},
+		{
+			name:     "encrypted",
+			pdf:      buildPDF("/Encrypt 4 0 R", catalog, pages, page, "<< /Filter /Standard /V 2 /R 3 /P -3904 >>"),
+			expected: []string{CodeEncrypted, CodeNotPDFA},
+		},
+		{
+			name:     "too many pages",
+			pdf:      buildPDF("", catalog, "<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >>", page, page),
+			opts:     &Options{MaxPages: 1},
+			expected: []string{CodeTooManyPages, CodeNotPDFA},
+		},
+		{
+			name:     "too large",
+			pdf:      buildPDF("", catalog, pages, page),
+			opts:     &Options{MaxSize: 100},
+			expected: []string{CodeTooLarge, CodeNotPDFA},
+		},
+		{
+			name:     "truncated",
+			pdf:      buildPDF("", catalog, pages, page)[:200],
+			expected: []string{CodeCorrupt, CodeCorrupt, CodeNotPDFA},
+		},
+		{
+			name:     "corrupt object stream",
+			pdf:      buildPDF("", catalog, pages, page, "stream:<< /Type /ObjStm /N 1 /First 4 /Filter /FlateDecode >>\nnot deflated"),
+			expected: []string{CodeCorrupt, CodeNotPDFA},
+		},
+	}
+
+	for _, test := range tests {
+		report := Check(test.pdf, "", test.opts)
+		assert.Equal(t, test.expected, codes(report.Issues), test.name)
+	}
+}
+
+func TestCheck_Severity(t *testing.T) {
+	report := Check(buildPDF("", catalog, pages, page, helvetica), "", nil)
+	assert.NoError(t, report.Err())
+
+	report = Check(buildPDF("", catalog, pages, page, helvetica), "", &Options{RequireEmbeddedFonts: true})
+	assert.Equal(t, []string{CodeFontsNotEmbedded}, codes(report.Errors()))
+	assert.Error(t, report.Err())
+
+	report = Check(buildPDF("/Encrypt 4 0 R", catalog, pages, page, "<< /Filter /Standard /V 2 /R 3 /P -3644 >>"), "", nil)
+	assert.Equal(t, &Permissions{Print: true, Modify: false, Copy: false, Annotate: false, FillForms: true}, report.PDF.Permissions)
+}
+
+func TestCheck_ObjectStream(t *testing.T) {
+	// The page and the JavaScript action are stored in a compressed object stream.
+	objects := "<< /Type /Page /Parent 2 0 R >> << /S /JavaScript /JS (app.alert\\(1\\)) >>"
+	header := "4 0 5 32 "
+	objStm := fmt.Sprintf("stream:<< /Type /ObjStm /N 2 /First %d /Filter /FlateDecode >>\n%s", len(header), deflate(header+objects))
+
+	report := Check(buildPDF("", catalog, pages, objStm), "", nil)
+	assert.Equal(t, 1, report.PDF.Pages)
+	assert.True(t, report.PDF.JavaScript)
+	assert.Equal(t, []string{CodeJavaScript, CodeNotPDFA}, codes(report.Issues))
+}
+
+func TestCheck_MalformedObjectStream(t *testing.T) {
+	tests := []struct {
+		name   string
+		objStm string
+	}{
+		{name: "negative offset", objStm: "stream:<< /Type /ObjStm /N 1 /First 4 >>\n2 -5 xx"},
+		{name: "negative next offset", objStm: "stream:<< /Type /ObjStm /N 2 /First 9 >>\n4 2 5 -3 xxxx"},
+		{name: "offset overflows", objStm: "stream:<< /Type /ObjStm /N 1 /First 22 >>\n4 9223372036854775807 xx"},
+		{name: "more objects than the header", objStm: "stream:<< /Type /ObjStm /N 9223372036854775807 /First 4 >>\n4 0 xx"},
+		{name: "offset after the end", objStm: "stream:<< /Type /ObjStm /N 1 /First 4 >>\n4 9 xx"},
+	}
+
+	for _, test := range tests {
+		assert.NotPanics(t, func() {
+			report := Check(buildPDF("", catalog, pages, test.objStm), "", nil)
+			assert.Contains(t, codes(report.Issues), CodeCorrupt, test.name)
+		}, test.name)
+	}
+}
+
+func TestCheck_StreamTooLarge(t *testing.T) {
+	// The object stream is a few kilobytes, but decompresses to a megabyte.
+	header := "4 0 "
+	content := header + "<< /Type /Page /Parent 2 0 R >>" + strings.Repeat(" ", 1<<20)
+	objStm := fmt.Sprintf("stream:<< /Type /ObjStm /N 1 /First %d /Filter /FlateDecode >>\n%s", len(header), deflate(content))
+	pdf := buildPDF("", catalog, pages, objStm)
+	assert.True(t, len(pdf) < 4<<10)
+
+	report := Check(pdf, "", &Options{MaxStreamSize: 1 << 16})
+	assert.Equal(t, []string{CodeStreamTooLarge, CodeCorrupt, CodeNotPDFA}, codes(report.Issues))
+	assert.Equal(t, "object stream 3 can't be decoded: decompressed stream is too large, the limit is 65536 bytes", report.Issues[0].Message)
+
+	report = Check(pdf, "", nil)
+	assert.Equal(t, 1, report.PDF.Pages)
+}
+
+func TestCheck_OtherTypes(t *testing.T) {
+	assert.Empty(t, Check([]byte("Contract"), "contract.txt", nil).Issues)
+	assert.Empty(t, Check([]byte(`<?xml version="1.0"?><contract/>`), "contract.xml", nil).Issues)
+	assert.Equal(t, []string{CodeUnsupportedType}, codes(Check([]byte{0, 1, 2}, "contract.bin", nil).Issues))
+	assert.Equal(t, []string{CodeEmpty}, codes(Check(nil, "contract.pdf", nil).Issues))
+}
@@ -0,0 +1,233 @@
+// Package redirect handles the signer returning to the success, cancel and error URLs when using RedirectModeRedirect.
+//
+// The query parameters of a redirect are controlled by the end user and can't be trusted. Settings signs them with an HMAC using
+// a secret key, and Handler rejects redirects without a valid signature, so a redirect can't be crafted for another document or
+// signer. Handler only uses the parameters to find the document and signer, and decides the outcome from the document retrieved
+// from the API.
+package redirect
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/larwef/signicat"
+)
+
+// Query parameters used in the redirect URLs.
+const (
+	ParamDocumentID       = "documentId"
+	ParamExternalID       = "externalId"
+	ParamExternalSignerID = "externalSignerId"
+	ParamResult           = "result"
+	// ParamSignature is the hex encoded HMAC-SHA256 of the other parameters.
+	ParamSignature = "signature"
+)
+
+// signedParams are the parameters covered by ParamSignature.
+var signedParams = []string{ParamDocumentID, ParamExternalID, ParamExternalSignerID, ParamResult}
+
+// Available results, as claimed by the redirect URL.
+const (
+	ResultSuccess = "success"
+	ResultCancel  = "cancel"
+	ResultError   = "error"
+)
+
+// Errors passed to OnError.
+var (
+	// ErrInvalidRedirect is used when the redirect is missing parameters, has an invalid signature or references an unknown
+	// document or signer.
+	ErrInvalidRedirect = errors.New("invalid redirect")
+	// ErrNotSigned is used when the redirect claims success, but the signer hasn't signed.
+	ErrNotSigned = errors.New("redirect claims success, but the signer hasn't signed")
+	// ErrSigningFailed is used when the signer was sent to the error URL.
+	ErrSigningFailed = errors.New("signing failed")
+)
+
+// errNoKey is used when Handler.Key isn't set.
+var errNoKey = errors.New("redirect: no key to verify redirects with")
+
+// Settings returns redirect settings pointing the signer back to baseURL with parameters identifying the document by its external
+// ID, the signer and the result, signed with key. Mount a Handler with the same key at baseURL.
+func Settings(baseURL, externalID, externalSignerID string, key []byte) (*signicat.RedirectSettings, error) {
+	if len(key) == 0 {
+		return nil, errNoKey
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	withResult := func(result string) string {
+		params := u.Query()
+		params.Set(ParamExternalID, externalID)
+		params.Set(ParamExternalSignerID, externalSignerID)
+		params.Set(ParamResult, result)
+		params.Set(ParamSignature, Sign(key, params))
+
+		ru := *u
+		ru.RawQuery = params.Encode()
+		return ru.String()
+	}
+
+	return &signicat.RedirectSettings{
+		RedirectMode: signicat.RedirectModeRedirect,
+		Success:      withResult(ResultSuccess),
+		Cancel:       withResult(ResultCancel),
+		Error:        withResult(ResultError),
+	}, nil
+}
+
+// Sign returns the signature of the parameters identifying the document, the signer and the result in params, for
+// ParamSignature. Settings signs the redirect URLs it returns, so Sign is only needed for URLs made otherwise.
+func Sign(key []byte, params url.Values) string {
+	signed := make(url.Values)
+	for _, name := range signedParams {
+		if v, ok := params[name]; ok {
+			signed[name] = v
+		}
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signed.Encode()))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Result is the confirmed outcome of a redirect.
+type Result struct {
+	Document *signicat.Document
+	Signer   *signicat.SignerResponse
+	// Claimed is the result claimed by the redirect URL. Only informative.
+	Claimed string
+}
+
+// Handler handles the redirects of signers returning from the signing page. Use Settings to create matching redirect settings.
+type Handler struct {
+	Signature *signicat.SignatureService
+	// Key is the key the redirect URLs are signed with. Every redirect is rejected if it isn't set.
+	Key []byte
+
+	// OnSigned is called when the signer has signed the document.
+	OnSigned func(res http.ResponseWriter, req *http.Request, result *Result)
+	// OnCanceled is called when the signer canceled, or the document is canceled or expired.
+	OnCanceled func(res http.ResponseWriter, req *http.Request, result *Result)
+	// OnError is called for everything else. result is nil if the document or signer couldn't be found. Without it, redirects
+	// are responded to with 400 Bad Request, errors from the API with 502 Bad Gateway and a missing Key with 500 Internal Server
+	// Error. The response only tells the kind of error, not the details.
+	OnError func(res http.ResponseWriter, req *http.Request, result *Result, err error)
+}
+
+func (h *Handler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	params := req.URL.Query()
+	claimed := params.Get(ParamResult)
+	externalSignerID := params.Get(ParamExternalSignerID)
+
+	if len(h.Key) == 0 {
+		h.error(res, req, nil, errNoKey)
+		return
+	}
+	if externalSignerID == "" || (params.Get(ParamDocumentID) == "" && params.Get(ParamExternalID) == "") {
+		h.error(res, req, nil, ErrInvalidRedirect)
+		return
+	}
+	if !hmac.Equal([]byte(params.Get(ParamSignature)), []byte(Sign(h.Key, params))) {
+		h.error(res, req, nil, fmt.Errorf("%w: invalid signature", ErrInvalidRedirect))
+		return
+	}
+
+	document, err := h.document(req, params)
+	if err != nil {
+		h.error(res, req, nil, err)
+		return
+	}
+
+	var signer *signicat.SignerResponse
+	for _, s := range document.Signers {
+		if s.ExternalSignerID == externalSignerID {
+			signer = s
+			break
+		}
+	}
+	if signer == nil {
+		h.error(res, req, nil, fmt.Errorf("%w: unknown signer %s", ErrInvalidRedirect, externalSignerID))
+		return
+	}
+
+	result := &Result{Document: document, Signer: signer, Claimed: claimed}
+
+	var status string
+	if document.Status != nil {
+		status = document.Status.DocumentStatus
+	}
+
+	switch {
+	case signer.DocumentSignature != nil:
+		h.signed(res, req, result)
+	case status == signicat.DocumentStatusCanceled || status == signicat.DocumentStatusExpired || claimed == ResultCancel:
+		h.canceled(res, req, result)
+	case claimed == ResultSuccess:
+		h.error(res, req, result, ErrNotSigned)
+	default:
+		h.error(res, req, result, ErrSigningFailed)
+	}
+}
+
+func (h *Handler) document(req *http.Request, params url.Values) (*signicat.Document, error) {
+	if documentID := params.Get(ParamDocumentID); documentID != "" {
+		return h.Signature.RetrieveDocument(req.Context(), documentID)
+	}
+
+	documents, err := h.Signature.ListDocuments(req.Context(), &signicat.ListDocumentsOptions{ExternalID: params.Get(ParamExternalID)})
+	if err != nil {
+		return nil, err
+	}
+	if len(documents) != 1 {
+		return nil, fmt.Errorf("%w: found %d documents with external id %s", ErrInvalidRedirect, len(documents), params.Get(ParamExternalID))
+	}
+
+	// The list doesn't necessarily include every detail, so get the whole document.
+	return h.Signature.RetrieveDocument(req.Context(), documents[0].DocumentID)
+}
+
+func (h *Handler) signed(res http.ResponseWriter, req *http.Request, result *Result) {
+	if h.OnSigned != nil {
+		h.OnSigned(res, req, result)
+		return
+	}
+	res.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) canceled(res http.ResponseWriter, req *http.Request, result *Result) {
+	if h.OnCanceled != nil {
+		h.OnCanceled(res, req, result)
+		return
+	}
+	res.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) error(res http.ResponseWriter, req *http.Request, result *Result, err error) {
+	if h.OnError != nil {
+		h.OnError(res, req, result, err)
+		return
+	}
+
+	switch {
+	case errors.Is(err, errNoKey):
+		http.Error(res, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	case errors.Is(err, ErrInvalidRedirect):
+		http.Error(res, ErrInvalidRedirect.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrNotSigned):
+		http.Error(res, ErrNotSigned.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrSigningFailed):
+		http.Error(res, ErrSigningFailed.Error(), http.StatusBadRequest)
+	default:
+		// Errors from the API aren't the fault of the signer, and their details aren't for the signer.
+		http.Error(res, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+	}
+}
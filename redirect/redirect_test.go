@@ -0,0 +1,185 @@
+package redirect
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/larwef/signicat"
+	"github.com/stretchr/testify/assert"
+)
+
+var key = []byte("someKey")
+
+// signed adds the signature to a query.
+func signed(t *testing.T, query string) string {
+	params, err := url.ParseQuery(query)
+	assert.NoError(t, err)
+	params.Set(ParamSignature, Sign(key, params))
+
+	return params.Encode()
+}
+
+func TestSettings(t *testing.T) {
+	settings, err := Settings("https://example.com/signed?tenant=1", "someExternalId", "someSigner", key)
+	assert.NoError(t, err)
+	assert.Equal(t, signicat.RedirectModeRedirect, settings.RedirectMode)
+
+	u, err := url.Parse(settings.Cancel)
+	assert.NoError(t, err)
+	assert.Equal(t, "/signed", u.Path)
+	params := u.Query()
+	assert.Equal(t, Sign(key, params), params.Get(ParamSignature))
+	assert.NotEqual(t, Sign([]byte("anotherKey"), params), params.Get(ParamSignature))
+	params.Del(ParamSignature)
+	assert.Equal(t, url.Values{
+		"tenant":              {"1"},
+		ParamExternalID:       {"someExternalId"},
+		ParamExternalSignerID: {"someSigner"},
+		ParamResult:           {ResultCancel},
+	}, params)
+
+	// The other parameters aren't signed, so the base URL can have any.
+	params.Set("tenant", "2")
+	assert.Equal(t, Sign(key, u.Query()), Sign(key, params))
+
+	_, err = Settings("https://example.com/signed", "someExternalId", "someSigner", nil)
+	assert.Error(t, err)
+}
+
+func TestHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := signicat.NewClientWithURL(&http.Client{}, server.URL)
+	assert.NoError(t, err)
+
+	documents := map[string]string{
+		"signed":   `{"documentId":"signed","status":{"documentStatus":"signed"},"signers":[{"externalSignerId":"signer","documentSignature":{"signatureMethod":"no_bankid_netcentric"}}]}`,
+		"unsigned": `{"documentId":"unsigned","status":{"documentStatus":"unsigned"},"signers":[{"externalSignerId":"signer"}]}`,
+		"canceled": `{"documentId":"canceled","status":{"documentStatus":"canceled"},"signers":[{"externalSignerId":"signer"}]}`,
+	}
+	mux.HandleFunc("/signature/documents/", func(res http.ResponseWriter, req *http.Request) {
+		document, ok := documents[req.URL.Path[len("/signature/documents/"):]]
+		if !ok {
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if _, err := io.WriteString(res, document); err != nil {
+			t.Fatal(err)
+		}
+	})
+	mux.HandleFunc("/signature/documents", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "someExternalId", req.URL.Query().Get("externalId"))
+		if _, err := io.WriteString(res, `[{"documentId":"signed"}]`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	var outcome string
+	var outcomeErr error
+	handler := &Handler{
+		Signature: client.Signature,
+		Key:       key,
+		OnSigned: func(res http.ResponseWriter, req *http.Request, result *Result) {
+			outcome = "signed"
+		},
+		OnCanceled: func(res http.ResponseWriter, req *http.Request, result *Result) {
+			outcome = "canceled"
+		},
+		OnError: func(res http.ResponseWriter, req *http.Request, result *Result, err error) {
+			outcome, outcomeErr = "error", err
+		},
+	}
+
+	tests := []struct {
+		query       string
+		expected    string
+		expectedErr error
+	}{
+		{query: "documentId=signed&externalSignerId=signer&result=success", expected: "signed"},
+		{query: "externalId=someExternalId&externalSignerId=signer&result=success", expected: "signed"},
+		// Claiming cancel doesn't change that the signer has signed.
+		{query: "documentId=signed&externalSignerId=signer&result=cancel", expected: "signed"},
+		{query: "documentId=unsigned&externalSignerId=signer&result=success", expected: "error", expectedErr: ErrNotSigned},
+		{query: "documentId=unsigned&externalSignerId=signer&result=cancel", expected: "canceled"},
+		{query: "documentId=unsigned&externalSignerId=signer&result=error", expected: "error", expectedErr: ErrSigningFailed},
+		{query: "documentId=canceled&externalSignerId=signer&result=success", expected: "canceled"},
+		{query: "documentId=signed&externalSignerId=someoneElse&result=success", expected: "error", expectedErr: ErrInvalidRedirect},
+		{query: "externalSignerId=signer&result=success", expected: "error", expectedErr: ErrInvalidRedirect},
+	}
+
+	for _, test := range tests {
+		outcome, outcomeErr = "", nil
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/signed?"+signed(t, test.query), nil))
+		assert.Equal(t, test.expected, outcome, test.query)
+		if test.expectedErr != nil {
+			assert.True(t, errors.Is(outcomeErr, test.expectedErr), test.query)
+		}
+	}
+
+	// The parameters can't be changed without the key.
+	for _, query := range []string{
+		"documentId=signed&externalSignerId=signer&result=success",
+		"documentId=signed&externalSignerId=signer&result=success&signature=" + Sign([]byte("anotherKey"), url.Values{
+			ParamDocumentID: {"signed"}, ParamExternalSignerID: {"signer"}, ParamResult: {"success"},
+		}),
+		strings.Replace(signed(t, "documentId=unsigned&externalSignerId=signer&result=success"), "unsigned", "signed", 1),
+	} {
+		outcome, outcomeErr = "", nil
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/signed?"+query, nil))
+		assert.Equal(t, "error", outcome, query)
+		assert.True(t, errors.Is(outcomeErr, ErrInvalidRedirect), query)
+	}
+}
+
+func TestHandler_DefaultErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := signicat.NewClientWithURL(&http.Client{}, server.URL)
+	assert.NoError(t, err)
+
+	mux.HandleFunc("/signature/documents/", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	tests := []struct {
+		handler      *Handler
+		query        string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			handler:      &Handler{Signature: client.Signature, Key: key},
+			query:        "/signed",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "invalid redirect\n",
+		},
+		{
+			handler:      &Handler{Signature: client.Signature, Key: key},
+			query:        "/signed?" + signed(t, "documentId=someDocumentId&externalSignerId=signer&result=success"),
+			expectedCode: http.StatusBadGateway,
+			expectedBody: "Bad Gateway\n",
+		},
+		{
+			handler:      &Handler{Signature: client.Signature},
+			query:        "/signed?" + signed(t, "documentId=someDocumentId&externalSignerId=signer&result=success"),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Internal Server Error\n",
+		},
+	}
+
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		test.handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, test.query, nil))
+		assert.Equal(t, test.expectedCode, res.Code, test.query)
+		assert.Equal(t, test.expectedBody, res.Body.String(), test.query)
+	}
+}
@@ -0,0 +1,303 @@
+package signicat
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reminder warnings returned when planning reminders.
+const (
+	ReminderWarningMaxNotReached = "fewer reminders than maxReminders will be sent before the deadline"
+	ReminderWarningTooFrequent   = "reminders are sent more often than once a day"
+	ReminderWarningUnbounded     = "no deadline or maxReminders, reminders are sent until the document is signed"
+)
+
+// How far ahead to look for the next run of a cron schedule before giving up.
+const cronSearchYears = 5
+
+var (
+	cronMonthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	cronDayNames = map[string]int{
+		"SUN": 1, "MON": 2, "TUE": 3, "WED": 4, "THU": 5, "FRI": 6, "SAT": 7,
+	}
+)
+
+// CronSchedule is a parsed Reminder.ChronSchedule. Signicat uses the Quartz cron dialect with the fields seconds, minutes, hours,
+// day of month, month, day of week and an optional year. Day of week is 1-7 starting on sunday, and exactly one of day of month and
+// day of week must be ?. The special characters L, W and # are not supported.
+type CronSchedule struct {
+	expr string
+
+	seconds, minutes, hours, daysOfMonth, months, daysOfWeek uint64
+	years                                                    map[int]bool
+	anyDayOfMonth, anyDayOfWeek                              bool
+}
+
+// ParseCronSchedule parses a cron expression in the Quartz dialect used by Signicat.
+func ParseCronSchedule(expr string) (*CronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 6 && len(fields) != 7 {
+		return nil, fmt.Errorf("cron schedule %q: expected 6 or 7 fields, got %d", expr, len(fields))
+	}
+
+	c := &CronSchedule{expr: expr}
+
+	var err error
+	if c.seconds, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("cron schedule %q: seconds: %v", expr, err)
+	}
+	if c.minutes, err = parseCronField(fields[1], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("cron schedule %q: minutes: %v", expr, err)
+	}
+	if c.hours, err = parseCronField(fields[2], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("cron schedule %q: hours: %v", expr, err)
+	}
+
+	c.anyDayOfMonth, c.anyDayOfWeek = fields[3] == "?", fields[5] == "?"
+	if c.anyDayOfMonth == c.anyDayOfWeek {
+		return nil, fmt.Errorf("cron schedule %q: exactly one of day of month and day of week must be ?", expr)
+	}
+	if !c.anyDayOfMonth {
+		if c.daysOfMonth, err = parseCronField(fields[3], 1, 31, nil); err != nil {
+			return nil, fmt.Errorf("cron schedule %q: day of month: %v", expr, err)
+		}
+	}
+	if c.months, err = parseCronField(fields[4], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("cron schedule %q: month: %v", expr, err)
+	}
+	if !c.anyDayOfWeek {
+		if c.daysOfWeek, err = parseCronField(fields[5], 1, 7, cronDayNames); err != nil {
+			return nil, fmt.Errorf("cron schedule %q: day of week: %v", expr, err)
+		}
+	}
+
+	if len(fields) == 7 && fields[6] != "*" {
+		c.years = make(map[int]bool)
+		for _, part := range strings.Split(fields[6], ",") {
+			from, to, step, err := parseCronRange(part, 1970, 2099, nil)
+			if err != nil {
+				return nil, fmt.Errorf("cron schedule %q: year: %v", expr, err)
+			}
+			for y := from; y <= to; y += step {
+				c.years[y] = true
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// String returns the cron expression.
+func (c *CronSchedule) String() string {
+	return c.expr
+}
+
+// Next returns the first time after t the schedule runs. Returns the zero time if the schedule doesn't run within the next years.
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Second).Add(time.Second)
+	limit := t.Year() + cronSearchYears
+
+wrap:
+	if t.Year() > limit {
+		return time.Time{}
+	}
+
+	if c.years != nil && !c.years[t.Year()] {
+		t = time.Date(t.Year()+1, time.January, 1, 0, 0, 0, 0, loc)
+		goto wrap
+	}
+
+	for !bit(c.months, int(t.Month())) {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !c.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for !bit(c.hours, t.Hour()) {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+
+	for !bit(c.minutes, t.Minute()) {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	for !bit(c.seconds, t.Second()) {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second()+1, 0, loc)
+		if t.Second() == 0 {
+			goto wrap
+		}
+	}
+
+	return t
+}
+
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	if c.anyDayOfMonth {
+		return bit(c.daysOfWeek, int(t.Weekday())+1)
+	}
+
+	return bit(c.daysOfMonth, t.Day())
+}
+
+// ReminderPlan is when reminders will be sent for a document.
+type ReminderPlan struct {
+	Times    []time.Time
+	Warnings []string
+}
+
+// Plan validates ChronSchedule and returns up to n of the reminders sent after from. No reminders are planned after the deadline in
+// ttl, and no more than MaxReminders are planned. The plan has warnings if the schedule is likely to be a mistake, eg. when
+// MaxReminders can't be reached before the deadline. n can't be negative.
+func (r *Reminder) Plan(from time.Time, ttl *TimeToLive, n int) (*ReminderPlan, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("invalid number of reminders to plan: %d", n)
+	}
+	schedule, err := ParseCronSchedule(r.ChronSchedule)
+	if err != nil {
+		return nil, err
+	}
+
+	var deadline *time.Time
+	if ttl != nil {
+		deadline = ttl.Deadline
+	}
+
+	limit := n
+	if r.MaxReminders > 0 && int(r.MaxReminders) > limit {
+		limit = int(r.MaxReminders)
+	}
+
+	var times []time.Time
+	for t := schedule.Next(from); !t.IsZero() && len(times) < limit; t = schedule.Next(t) {
+		if deadline != nil && t.After(*deadline) {
+			break
+		}
+		times = append(times, t)
+	}
+
+	plan := &ReminderPlan{}
+
+	if r.MaxReminders > 0 && len(times) < int(r.MaxReminders) {
+		plan.Warnings = append(plan.Warnings, ReminderWarningMaxNotReached)
+	}
+
+	for i := 1; i < len(times); i++ {
+		if times[i].Sub(times[i-1]) < 24*time.Hour {
+			plan.Warnings = append(plan.Warnings, ReminderWarningTooFrequent)
+			break
+		}
+	}
+
+	if deadline == nil && r.MaxReminders <= 0 {
+		plan.Warnings = append(plan.Warnings, ReminderWarningUnbounded)
+	}
+
+	if r.MaxReminders > 0 && len(times) > int(r.MaxReminders) {
+		times = times[:r.MaxReminders]
+	}
+	if len(times) > n {
+		times = times[:n]
+	}
+	plan.Times = times
+
+	return plan, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges and steps into a bitset.
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		from, to, step, err := parseCronRange(part, min, max, names)
+		if err != nil {
+			return 0, err
+		}
+		for i := from; i <= to; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+// parseCronRange parses one of *, value, from-to, */step, value/step and from-to/step.
+func parseCronRange(part string, min, max int, names map[string]int) (from, to, step int, err error) {
+	step = 1
+	stepped := false
+	if i := strings.Index(part, "/"); i >= 0 {
+		stepped = true
+		if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+			return 0, 0, 0, fmt.Errorf("invalid step in %q", part)
+		}
+		part = part[:i]
+	}
+
+	switch {
+	case part == "*":
+		return min, max, step, nil
+	case strings.Contains(part, "-"):
+		bounds := strings.SplitN(part, "-", 2)
+		if from, err = parseCronValue(bounds[0], min, max, names); err != nil {
+			return 0, 0, 0, err
+		}
+		if to, err = parseCronValue(bounds[1], min, max, names); err != nil {
+			return 0, 0, 0, err
+		}
+		if from > to {
+			return 0, 0, 0, fmt.Errorf("invalid range %q", part)
+		}
+	default:
+		if from, err = parseCronValue(part, min, max, names); err != nil {
+			return 0, 0, 0, err
+		}
+		to = from
+		// A single value with a step, eg. 0/15, means starting at the value.
+		if stepped {
+			to = max
+		}
+	}
+
+	return from, to, step, nil
+}
+
+func parseCronValue(value string, min, max int, names map[string]int) (int, error) {
+	if n, ok := names[strings.ToUpper(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		if strings.ContainsAny(value, "LW#") {
+			return 0, fmt.Errorf("unsupported value %q", value)
+		}
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", n, min, max)
+	}
+
+	return n, nil
+}
+
+func bit(bits uint64, i int) bool {
+	return bits&(1<<uint(i)) != 0
+}
@@ -0,0 +1,103 @@
+package signicat
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	valid := []string{
+		"0 0 12 * * ?",
+		"0 0 12 ? * MON-FRI",
+		"0 0 12 1/1 * ? *",
+		"0 30 9,15 ? * 2,4,6 2020-2030",
+		"0 0 0/6 * JAN-MAR ?",
+		"0 */15 * * * ?",
+	}
+	for _, expr := range valid {
+		_, err := ParseCronSchedule(expr)
+		assert.NoError(t, err, expr)
+	}
+
+	invalid := []string{
+		"",
+		"0 12 * * *",
+		"0 0 12 * * *",
+		"0 0 12 ? * ?",
+		"0 0 24 * * ?",
+		"0 60 12 * * ?",
+		"0 0 12 ? * 8",
+		"0 0 12 L * ?",
+		"0 0 12 ? * 6#3",
+		"0 0 12 10-5 * ?",
+		"0 0 12 * * ? 1900",
+		"0 0 12 */0 * ?",
+	}
+	for _, expr := range invalid {
+		_, err := ParseCronSchedule(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	from := time.Date(2020, 6, 26, 13, 0, 0, 0, time.UTC) // Friday
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{expr: "0 0 12 * * ?", expected: time.Date(2020, 6, 27, 12, 0, 0, 0, time.UTC)},
+		{expr: "0 0 12 ? * MON-FRI", expected: time.Date(2020, 6, 29, 12, 0, 0, 0, time.UTC)},
+		{expr: "0 0 12 ? * 1", expected: time.Date(2020, 6, 28, 12, 0, 0, 0, time.UTC)},
+		{expr: "0 */15 * * * ?", expected: time.Date(2020, 6, 26, 13, 15, 0, 0, time.UTC)},
+		{expr: "0 0 8 31 * ?", expected: time.Date(2020, 7, 31, 8, 0, 0, 0, time.UTC)},
+		{expr: "0 0 8 1 JAN ?", expected: time.Date(2021, 1, 1, 8, 0, 0, 0, time.UTC)},
+		{expr: "0 0 8 29 FEB ? 2021-2030", expected: time.Date(2024, 2, 29, 8, 0, 0, 0, time.UTC)},
+		{expr: "0 0 8 30 FEB ?", expected: time.Time{}},
+	}
+
+	for _, test := range tests {
+		schedule, err := ParseCronSchedule(test.expr)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, schedule.Next(from), test.expr)
+	}
+}
+
+func TestReminder_Plan(t *testing.T) {
+	from := time.Date(2020, 6, 26, 13, 0, 0, 0, time.UTC)
+	deadline := time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+	ttl := &TimeToLive{Deadline: &deadline}
+
+	reminder := &Reminder{ChronSchedule: "0 0 12 * * ?", MaxReminders: 3}
+	plan, err := reminder.Plan(from, ttl, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2020, 6, 27, 12, 0, 0, 0, time.UTC),
+		time.Date(2020, 6, 28, 12, 0, 0, 0, time.UTC),
+		time.Date(2020, 6, 29, 12, 0, 0, 0, time.UTC),
+	}, plan.Times)
+	assert.Empty(t, plan.Warnings)
+
+	reminder = &Reminder{ChronSchedule: "0 0 12 ? * MON", MaxReminders: 3}
+	plan, err = reminder.Plan(from, ttl, 10)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Times, 1)
+	assert.Equal(t, []string{ReminderWarningMaxNotReached}, plan.Warnings)
+
+	reminder = &Reminder{ChronSchedule: "0 0 * * * ?"}
+	plan, err = reminder.Plan(from, nil, 2)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Times, 2)
+	assert.Equal(t, []string{ReminderWarningTooFrequent, ReminderWarningUnbounded}, plan.Warnings)
+
+	_, err = (&Reminder{ChronSchedule: "every day"}).Plan(from, ttl, 1)
+	assert.Error(t, err)
+
+	plan, err = reminder.Plan(from, nil, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, plan.Times)
+
+	_, err = reminder.Plan(from, nil, -1)
+	assert.EqualError(t, err, "invalid number of reminders to plan: -1")
+}
@@ -0,0 +1,127 @@
+// Package sdo parses the Signicat signed data object (SDO), the file retrieved with signicat.FileFormatStandardPackaging, into the
+// evidence of each signature.
+//
+// An SDO is an XML document holding one XML signature (XMLDSig with XAdES properties) per signer, each over the original
+// document. The signatures are read, not verified.
+package sdo
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"time"
+
+	"github.com/larwef/signicat"
+	"github.com/larwef/signicat/internal/xmldsig"
+)
+
+// ErrNoSignatures is returned when the SDO has no signatures.
+var ErrNoSignatures = errors.New("no signatures found")
+
+// SDO is a parsed signed data object.
+type SDO struct {
+	Signatures []*Signature
+}
+
+// Signature is the evidence of a single signature in an SDO.
+type Signature struct {
+	// ID is the id attribute of the signature element.
+	ID string
+	// Algorithm is the XMLDSig signature algorithm URI, eg. http://www.w3.org/2001/04/xmldsig-more#rsa-sha256.
+	Algorithm string
+	Value     []byte
+	// Certificate is the certificate of the signer, and Chain the rest of the certificates in the signature, ordered towards the
+	// root.
+	Certificate *x509.Certificate
+	Chain       []*x509.Certificate
+	// SignedTime is the signing time claimed in the signature.
+	SignedTime *time.Time
+	// Timestamps are the times from the signature timestamps, added by a timestamp authority.
+	Timestamps []time.Time
+	// DocumentDigest is the digest of the original document which was signed.
+	DocumentDigest *Digest
+}
+
+// Digest is the digest of signed data.
+type Digest struct {
+	// Algorithm is the XMLDSig digest method URI, eg. http://www.w3.org/2001/04/xmlenc#sha256.
+	Algorithm string
+	Value     []byte
+}
+
+// Parse parses an SDO. Returns ErrNoSignatures if there are no signatures in it.
+func Parse(data []byte) (*SDO, error) {
+	signatures, err := xmldsig.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(signatures) == 0 {
+		return nil, ErrNoSignatures
+	}
+
+	sdo := &SDO{}
+	for _, s := range signatures {
+		signature := &Signature{
+			ID:         s.ID,
+			Algorithm:  s.SignatureMethod,
+			Value:      s.SignatureValue,
+			SignedTime: s.SigningTime,
+		}
+		if len(s.Certificates) > 0 {
+			signature.Certificate = s.Certificates[0]
+			signature.Chain = s.Certificates[1:]
+		}
+		for _, ts := range s.Timestamps {
+			signature.Timestamps = append(signature.Timestamps, ts.Time)
+		}
+		if refs := s.DataReferences(); len(refs) > 0 {
+			signature.DocumentDigest = &Digest{Algorithm: refs[0].DigestMethod, Value: refs[0].DigestValue}
+		}
+
+		sdo.Signatures = append(sdo.Signatures, signature)
+	}
+
+	return sdo, nil
+}
+
+// DocumentDigest returns the digest of the original document, taken from the first signature which has one.
+func (s *SDO) DocumentDigest() *Digest {
+	for _, signature := range s.Signatures {
+		if signature.DocumentDigest != nil {
+			return signature.DocumentDigest
+		}
+	}
+	return nil
+}
+
+// DocumentSignature returns the signature in the shape of the signicat.DocumentSignature of a signer, with the values which can be
+// read from the signature. The name and unique ID are taken from the common name and serial number in the subject of the
+// certificate. SignatureMethod is left empty, as the signature doesn't tell it.
+func (s *Signature) DocumentSignature() *signicat.DocumentSignature {
+	ds := &signicat.DocumentSignature{
+		SignedTime: s.SignedTime,
+		Mechanism:  signicat.MechanismsPkiSignature,
+	}
+	if ds.SignedTime == nil && len(s.Timestamps) > 0 {
+		ds.SignedTime = &s.Timestamps[0]
+	}
+	if s.Certificate != nil {
+		ds.FullName = s.Certificate.Subject.CommonName
+		ds.SignatureMethodUniqueID = s.Certificate.Subject.SerialNumber
+	}
+
+	return ds
+}
+
+// Matches reports whether the digest is the digest of content. It reports false if the algorithm is unknown.
+func (d *Digest) Matches(content []byte) bool {
+	h, ok := xmldsig.DigestMethod(d.Algorithm)
+	if !ok {
+		return false
+	}
+
+	hash := h.New()
+	hash.Write(content)
+
+	return bytes.Equal(hash.Sum(nil), d.Value)
+}
@@ -0,0 +1,59 @@
+package sdo
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/larwef/signicat"
+	"github.com/larwef/signicat/internal/testpki"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	document := []byte("%PDF-1.7 contract")
+	ola, ca := testpki.Chain("Ola Nordmann", "9578-6000-4-123456")
+	kari, _ := testpki.Chain("Kari Nordmann", "9578-6000-4-654321")
+	signed := time.Date(2020, 6, 26, 12, 0, 0, 0, time.UTC)
+	stamped := signed.Add(time.Minute)
+
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<sdo:SDO xmlns:sdo="http://www.signicat.com/sdo">
+  <sdo:Signatures>` +
+		testpki.Signature(&testpki.SignatureOptions{
+			ID: "ola", Document: document, Certificates: []*x509.Certificate{ola, ca}, SigningTime: &signed, Timestamp: &stamped,
+		}) +
+		testpki.Signature(&testpki.SignatureOptions{
+			ID: "kari", Document: document, Certificates: []*x509.Certificate{kari}, Timestamp: &stamped,
+		}) + `
+  </sdo:Signatures>
+</sdo:SDO>`)
+
+	sdo, err := Parse(data)
+	assert.NoError(t, err)
+	assert.Len(t, sdo.Signatures, 2)
+
+	s := sdo.Signatures[0]
+	assert.Equal(t, "ola", s.ID)
+	assert.Equal(t, ola, s.Certificate)
+	assert.Equal(t, []*x509.Certificate{ca}, s.Chain)
+	assert.Equal(t, []time.Time{stamped}, s.Timestamps)
+	assert.True(t, s.DocumentDigest.Matches(document))
+	assert.False(t, s.DocumentDigest.Matches([]byte("another document")))
+	assert.True(t, sdo.DocumentDigest().Matches(document))
+
+	assert.Equal(t, &signicat.DocumentSignature{
+		FullName:                "Ola Nordmann",
+		SignedTime:              &signed,
+		SignatureMethodUniqueID: "9578-6000-4-123456",
+		Mechanism:               signicat.MechanismsPkiSignature,
+	}, s.DocumentSignature())
+
+	// Without a claimed signing time, the timestamp is used.
+	assert.Equal(t, stamped, *sdo.Signatures[1].DocumentSignature().SignedTime)
+}
+
+func TestParse_NoSignatures(t *testing.T) {
+	_, err := Parse([]byte(`<sdo:SDO xmlns:sdo="http://www.signicat.com/sdo"/>`))
+	assert.Equal(t, ErrNoSignatures, err)
+}
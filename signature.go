@@ -6,119 +6,12 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
-	"time"
 )
 
-// Available redirection modes.
-const (
-	RedirectModeDoNotRedirect                     = "donot_redirect"
-	RedirectModeRedirect                          = "redirect"
-	RedirectModeIframeWithWebMessaging            = "iframe_with_webmessaging"
-	RedirectModeIframeWithRedirect                = "iframe_with_redirect"
-	RedirectModeIframeWithRedirectAndWebMessaging = "iframe_with_redirect_and_webmessaging"
-
-	// Available signature mechanisms.
-	MechanismsPkiSignature                  = "pkisignature"
-	MechanismsIdentification                = "identification"
-	MechanismsHandwritten                   = "handwritten"
-	MechanismsHandWrittenWithIdentification = "handwritten_with_identification"
-
-	// Available auth mechanism
-	AuthMechanismOff          = "off"
-	AuthMechanismEid          = "eid"
-	AuthMechanismSmsOtp       = "smsOtp"
-	AuthMechanismEidAndSmsOtp = "eidAndSmsOtp"
-
-	// Available color themes.
-	ColorThemeDefault    = "Default"
-	ColorThemeBlack      = "Black"
-	ColorThemeBlue       = "Blue"
-	ColorThemeCyan       = "Cyan"
-	ColorThemeDark       = "Dark"
-	ColorThemeLime       = "Lime"
-	ColorThemeNeutral    = "Neutral"
-	ColorThemePink       = "Pink"
-	ColorThemePurple     = "Purple"
-	ColorThemeRed        = "Red"
-	ColorThemeTeal       = "Teal"
-	ColorThemeIndigo     = "Indigo"
-	ColorThemeLightBlue  = "LightBlue"
-	ColorThemeDeepPurple = "DeepPurple"
-	ColorThemeGreen      = "Green"
-	ColorThemeLightGreen = "LightGreen"
-	ColorThemeYellow     = "Yellow"
-	ColorThemeAmber      = "Amber"
-	ColorThemeOrange     = "Orange"
-	ColorThemeDeepOrange = "DeepOrange"
-	ColorThemeBrown      = "Brown"
-	ColorThemeGray       = "Gray"
-	ColorThemeBlueGray   = "BlueGray"
-	ColorThemeOceanGreen = "OceanGreen"
-	ColorThemeGreenOcean = "GreenOcean"
-
-	// Available theme modes.
-	ThemeModeDefault = "Default"
-	ThemeModeLight   = "Light"
-	ThemeModeDark    = "Dark"
-
-	// Available spinners.
-	SpinnerDocument = "Document"
-	SpinnerClassic  = "Classic"
-	SpinnerCubes    = "Cubes"
-	SpinnerBounce   = "Bounce"
-
-	// Available top bars.
-	TopBarDefault  = "Default"
-	TopBarVisible  = "Visible"
-	TopBarOnlyMenu = "OnlyMenu"
-	TopBarHidden   = "Hidden"
-
-	// Available notification setups.
-	NotificationSetupOff       = "off"
-	NotificationSetupSendSms   = "sendSms"
-	NotificationSetupSendEmail = "sendEmail"
-	NotificationSetupSendBoth  = "sendBoth"
-
-	// Available signature methods.
-	SignatureMethodNoBankIDMobile     = "no_bankid_mobile"
-	SignatureMethodNoBankIDNetCentric = "no_bankid_netcentric"
-	SignatureMethodNoBuypass          = "no_buypass"
-	SignatureMethodSeBankID           = "se_bankid"
-	SignatureMethodDkNemID            = "dk_nemid"
-	SignatureMethodFiTupas            = "fi_tupas"
-	SignatureMethodFiMobiilivarmenne  = "fi_mobiilivarmenne"
-	SignatureMethodFiEid              = "fi_eid"
-	SignatureMethodSmsOtp             = "sms_otp"
-	SignatureMethodUnknown            = "unknown"
-
-	// Avalable options for personalInfoOrigin field.
-	PersonalInfoOriginUnknown       = "unknown"
-	PersonalInfoOriginEid           = "eid"
-	PersonalInfoOriginUserFormInput = "userFormInput"
-
-	// Available document statuses.
-	DocumentStatusUnsigned              = "unsigned"
-	DocumentStatusWaitingForAttachments = "waiting_for_attachments"
-	DocumentStatusPartialSigned         = "partialsigned"
-	DocumentStatusSigned                = "signed"
-	DocumentStatusCanceled              = "canceled"
-	DocumentStatusExpired               = "expired"
-
-	// Available file formats.
-	FileFormatUnsigned          = "unsigned"
-	FileFormatNative            = "native"
-	FileFormatStandardPackaging = "standard_packaging"
-	FileFormatPades             = "pades"
-	FileFormatXades             = "xades"
-
-	// Available languages.
-	LanguageEnglish   = "EN"
-	LanguageNorwegian = "NO"
-	LanguageDanish    = "DA"
-	LanguageSweedish  = "SV"
-	LanguageFinnish   = "FI"
+//go:generate go run ./internal/cmd/modelgen -spec api/signature.json -overlay api/signature.overlay.json -out signature_models.go
 
-	// Available merge-fields. See https://developer.signicat.io/docs/signature/create-document.html#notification-merge-fields.
+// Available merge-fields. See https://developer.signicat.io/docs/signature/create-document.html#notification-merge-fields.
+const (
 	MergeFieldDocumentTitle          = "{document-title}"
 	MergeFieldDocumentDescription    = "{document-description}"
 	MergeFieldSignableDocumetTitles  = "{signable-document-titles}"
@@ -137,35 +30,62 @@ type SignatureService service
 // CreateDocument creates a new document. In the response you will receive a document ID to retrieve info about the document at a
 // later time. You also receive a URL and unique identifier per signer.
 func (s *SignatureService) CreateDocument(ctx context.Context, createReq *CreateDocumentRequest) (*Document, error) {
-	req, err := s.client.NewRequest(http.MethodPost, "/signature/documents", createReq)
+	return s.createDocument(ctx, createReq, "")
+}
+
+// RetrieveDocument retrieves details of a single document.
+func (s *SignatureService) RetrieveDocument(ctx context.Context, documentID string) (*Document, error) {
+	u := fmt.Sprintf("/signature/documents/%s", documentID)
+	req, err := s.client.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	response := new(Document)
-	if err := s.client.Do(ctx, req, &response); err != nil {
+	if err := s.client.Do(ctx, req, response); err != nil {
 		return nil, err
 	}
 
 	return response, nil
 }
 
-// RetrieveDocument retrieves details of a single document.
-func (s *SignatureService) RetrieveDocument(ctx context.Context, documentID string) (*Document, error) {
-	u := fmt.Sprintf("/signature/documents/%s", documentID)
-	req, err := s.client.NewRequest(http.MethodGet, u, nil)
+// ListDocuments lists the documents matching the options. Options left empty are not used to filter.
+func (s *SignatureService) ListDocuments(ctx context.Context, opts *ListDocumentsOptions) ([]*Document, error) {
+	u, err := url.Parse("/signature/documents")
 	if err != nil {
 		return nil, err
 	}
 
-	response := new(Document)
-	if err := s.client.Do(ctx, req, response); err != nil {
+	if opts != nil {
+		params := u.Query()
+		if opts.ExternalID != "" {
+			params.Set("externalId", opts.ExternalID)
+		}
+		if opts.Status != "" {
+			params.Set("status", opts.Status)
+		}
+		u.RawQuery = params.Encode()
+	}
+
+	req, err := s.client.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []*Document
+	if err := s.client.Do(ctx, req, &response); err != nil {
 		return nil, err
 	}
 
 	return response, nil
 }
 
+// ListDocumentsOptions holds the filters used when listing documents.
+type ListDocumentsOptions struct {
+	ExternalID string
+	Status     string
+}
+
 // RetrieveDocumentStatus gets the status of a document.
 func (s *SignatureService) RetrieveDocumentStatus(ctx context.Context, documentID string) (*Status, error) {
 	u := fmt.Sprintf("/signature/documents/%s/status", documentID)
@@ -206,258 +126,3 @@ func (s *SignatureService) RetrieveFile(ctx context.Context, documentID, format
 
 	return nil
 }
-
-// CreateDocumentRequest is ...
-type CreateDocumentRequest struct {
-	Title          string           `json:"title"`
-	Signers        []*SignerRequest `json:"signers"`
-	DataToSign     *DataToSign      `json:"dataToSign"`
-	ContactDetails *ContactDetails  `json:"contactDetails"`
-	ExternalID     string           `json:"externalId"`
-	Description    string           `json:"description,omitempty"`
-	Notification   *Notification    `json:"notification"`
-	Advanced       *Advanced        `json:"advanced,omitempty"`
-}
-
-// SignerRequest is ...
-type SignerRequest struct {
-	ExternalSignerID string            `json:"externalSignerId"`
-	RedirectSettings *RedirectSettings `json:"redirectSettings"`
-	SignatureType    *SignatureType    `json:"signatureType"`
-	SignerInfo       *SignerInfo       `json:"signerInfo,omitempty"`
-	Authentication   *Authentication   `json:"authentication,omitempty"`
-	UI               *UI               `json:"ui,omitempty"`
-	Notifications    *Notifications    `json:"notifications,omitempty"`
-}
-
-// RedirectSettings is ...
-type RedirectSettings struct {
-	RedirectMode string `json:"redirectMode"`
-	Domain       string `json:"domain,omitempty"`
-	Error        string `json:"error,omitempty"`
-	Cancel       string `json:"cancel,omitempty"`
-	Success      string `json:"success,omitempty"`
-}
-
-// SignatureType is ...
-type SignatureType struct {
-	Mechanism string `json:"mechanism"`
-}
-
-// Authentication is ...
-type Authentication struct {
-	Mechanism               string `json:"mechanism,omitempty"`
-	SocialSecurityNumber    string `json:"socialSecurityNumber,omitempty"`
-	SignatureMethodUniqueID string `json:"signatureMethodUniqueId,omitempty"`
-}
-
-// UI is ...
-type UI struct {
-	Language string   `json:"language,omitempty"`
-	Styling  *Styling `json:"styling,omitempty"`
-}
-
-// Styling is ...
-type Styling struct {
-	ColorTheme      string `json:"colorTheme,omitempty"`
-	ThemeMode       string `json:"themeMode,omitempty"`
-	Spinner         string `json:"spinner,omitempty"`
-	TopBar          string `json:"topBar,omitempty"`
-	BackgroundColor string `json:"backgroundColor"`
-}
-
-// SignerInfo is ...
-type SignerInfo struct {
-	FirstName            string            `json:"firstName,omitempty"`
-	LastName             string            `json:"lastName,omitempty"`
-	Email                string            `json:"email,omitempty"`
-	SocialSecurityNumber string            `json:"socialSecurityNumber,omitempty"`
-	Mobile               *Mobile           `json:"mobile,omitempty"`
-	OrganizationInfo     *OrganizationInfo `json:"organizationInfo,omitempty"`
-}
-
-// Mobile is ...
-type Mobile struct {
-	CountryCode string `json:"countryCode,omitempty"`
-	Number      string `json:"number,omitempty"`
-}
-
-// OrganizationInfo is ...
-type OrganizationInfo struct {
-	OrgNo       string `json:"orgNo,omitempty"`
-	CompanyName string `json:"companyName,omitempty"`
-	CountryCode string `json:"countryCode,omitempty"`
-}
-
-// DataToSign is ...
-type DataToSign struct {
-	Title         string `json:"title,omitempty"`
-	Description   string `json:"description,omitempty"`
-	Base64Content string `json:"base64Content"`
-	FileName      string `json:"fileName"`
-	ConvertToPDF  bool   `json:"convertToPdf,omitempty"`
-}
-
-// ContactDetails is ...
-type ContactDetails struct {
-	Name  string `json:"name,omitempty"`
-	Phone string `json:"phone,omitempty"`
-	Email string `json:"email"`
-	URL   string `json:"url,omitempty"`
-}
-
-// Notification is ...
-type Notification struct {
-	SignRequest      *SignRequest      `json:"signRequest,omitEmpty"`
-	Reminder         *Reminder         `json:"reminder,omitEmpty"`
-	SignatureReceipt *SignatureReceipt `json:"signatureReceipt,omitEmpty"`
-	FinalReceipt     *FinalReceipt     `json:"finalReceipt,omitEmpty"`
-	CanceledReceipt  *CanceledReceipt  `json:"canceledReceipt,omitEmpty"`
-	ExpiredReceipt   *ExpiredReceipt   `json:"expiredReceipt,omitEmpty"`
-}
-
-// SignRequest is ...
-type SignRequest struct {
-	IncludeOriginalFile bool     `json:"includeOriginalFile,omitEmpty"`
-	Email               []*Email `json:"email,omitEmpty"`
-	Sms                 []*Sms   `json:"sms,omitEmpty"`
-}
-
-// Email is ...
-type Email struct {
-	Language   string `json:"language"`
-	Subject    string `json:"subject,omitEmpty"`
-	Text       string `json:"text,omitEmpty"`
-	SenderName string `json:"senderName,omitEmpty"`
-}
-
-// Sms is ...
-type Sms struct {
-	Language string `json:"language"`
-	Text     string `json:"text,omitEmpty"`
-	Sender   string `json:"sender,omitEmpty"`
-}
-
-// Advanced is ..
-type Advanced struct {
-	TimeToLive *TimeToLive `json:"timeToLive,omitempty"`
-}
-
-// TimeToLive is ...
-type TimeToLive struct {
-	Deadline         *time.Time `json:"deadline,omitempty"`
-	DeleteAfterHours int32      `json:"deleteAfterHours,omitempty"`
-}
-
-// Reminder is ...
-type Reminder struct {
-	ChronSchedule string   `json:"chronSchedule"`
-	MaxReminders  int32    `json:"maxReminders,omitempty"`
-	Email         []*Email `json:"email,omitempty"`
-	Sms           []*Sms   `json:"sms,omitempty"`
-}
-
-// SignatureReceipt is ...
-type SignatureReceipt struct {
-	Email []*Email `json:"email,omitempty"`
-	Sms   []*Sms   `json:"sms,omitempty"`
-}
-
-// FinalReceipt is ...
-type FinalReceipt struct {
-	AdditionalRecipients []*AdditionalRecipient `json:"additionalRecipients,omitempty"`
-	IncludeSignedFile    bool                   `json:"includeSignedFile,omitempty"`
-	Email                []*Email               `json:"email,omitempty"`
-	Sms                  []*Sms                 `json:"sms,omitempty"`
-}
-
-// AdditionalRecipient is ...
-type AdditionalRecipient struct {
-	Language          string            `json:"language,omitempty"`
-	Email             string            `json:"email"`
-	SustomMergeFields map[string]string `json:"sustomMergeFields,omitempty"`
-}
-
-// CanceledReceipt is ...
-type CanceledReceipt struct {
-	Email []*Email `json:"email,omitempty"`
-	Sms   []*Sms   `json:"sms,omitempty"`
-}
-
-// ExpiredReceipt is ...
-type ExpiredReceipt struct {
-	Email []*Email `json:"email,omitempty"`
-	Sms   []*Sms   `json:"sms,omitempty"`
-}
-
-// Notifications is ...
-type Notifications struct {
-	Setup *Setup `json:"setup,omitempty"`
-}
-
-// Setup is ...
-type Setup struct {
-	Request          string `json:"request,omitempty"`
-	Reminder         string `json:"reminder,omitempty"`
-	SignatureReceipt string `json:"signatureReceipt,omitempty"`
-	FinalReceipt     string `json:"finalReceipt,omitempty"`
-	Canceled         string `json:"canceled,omitempty"`
-	Expired          string `json:"expired,omitempty"`
-}
-
-// Document is ...
-type Document struct {
-	DocumentID     string            `json:"documentId,omitempty"`
-	Signers        []*SignerResponse `json:"signers,omitempty"`
-	Status         *Status           `json:"status,omitempty"`
-	Title          string            `json:"title,omitempty"`
-	Description    string            `json:"description,omitempty"`
-	ExternalID     string            `json:"externalId,omitempty"`
-	DataToSign     *DataToSign       `json:"dataToSign,omitempty"`
-	ContactDetails *ContactDetails   `json:"contactDetails,omitempty"`
-	Advanced       *Advanced         `json:"advanced,omitempty"`
-}
-
-// SignerResponse is ...
-type SignerResponse struct {
-	ID                      string             `json:"id,omitempty"`
-	URL                     string             `json:"url,omitempty"`
-	DocumentSignature       *DocumentSignature `json:"documentSignature,omitempty"`
-	ExternalSignerID        string             `json:"externalSignerId,omitempty"`
-	RedirectSettings        *RedirectSettings  `json:"redirectSettings,omitempty"`
-	SignatureType           *SignatureType     `json:"signatureType,omitempty"`
-	SignerInfo              *SignerInfo        `json:"signerInfo,omitempty"`
-	Notifications           *Notifications     `json:"notifications,omitempty"`
-	Order                   int32              `json:"order,omitempty"`
-	Required                bool               `json:"required,omitempty"`
-	SignURLExpires          *time.Time         `json:"signUrlExpires,omitempty"`
-	GetSocialSecurityNumber bool               `json:"getSocialSecurityNumber,omitempty"`
-}
-
-// DocumentSignature is ...
-type DocumentSignature struct {
-	SignatureMethod         string                `json:"signatureMethod"`
-	FullName                string                `json:"fullName,omitempty"`
-	FirstName               string                `json:"firstName,omitempty"`
-	LastName                string                `json:"lastName,omitempty"`
-	MiddleName              string                `json:"middleName,omitempty"`
-	SignedTime              *time.Time            `json:"signedTime,omitempty"`
-	DateOfBirth             string                `json:"dateOfBirth,omitempty"`
-	SignatureMethodUniqueID string                `json:"signatureMethodUniqueId,omitempty"`
-	SocialSecurityNumber    *SocialSecurityNumber `json:"socialSecurityNumber,omitempty"`
-	ClientIP                string                `json:"clientIp,omitempty"`
-	Mechanism               string                `json:"mechanism,omitempty"`
-	PersonalInfoOrigin      string                `json:"personalInfoOrigin,omitempty"`
-}
-
-// SocialSecurityNumber is ...
-type SocialSecurityNumber struct {
-	Value       string `json:"value,omitempty"`
-	CountryCode string `json:"countryCode,omitempty"`
-}
-
-// Status is ...
-type Status struct {
-	DocumentStatus    string   `json:"documentStatus,omitempty"`
-	CompletedPackages []string `json:"completedPackages,omitempty"`
-}
@@ -0,0 +1,393 @@
+// Code generated by modelgen from api/signature.json. DO NOT EDIT.
+
+package signicat
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Available redirection modes.
+const (
+	RedirectModeDoNotRedirect                     = "donot_redirect"
+	RedirectModeRedirect                          = "redirect"
+	RedirectModeIframeWithWebMessaging            = "iframe_with_webmessaging"
+	RedirectModeIframeWithRedirect                = "iframe_with_redirect"
+	RedirectModeIframeWithRedirectAndWebMessaging = "iframe_with_redirect_and_webmessaging"
+
+	// Available signature mechanisms.
+	MechanismsPkiSignature                  = "pkisignature"
+	MechanismsIdentification                = "identification"
+	MechanismsHandwritten                   = "handwritten"
+	MechanismsHandWrittenWithIdentification = "handwritten_with_identification"
+
+	// Available auth mechanism
+	AuthMechanismOff          = "off"
+	AuthMechanismEid          = "eid"
+	AuthMechanismSmsOtp       = "smsOtp"
+	AuthMechanismEidAndSmsOtp = "eidAndSmsOtp"
+
+	// Available color themes.
+	ColorThemeDefault    = "Default"
+	ColorThemeBlack      = "Black"
+	ColorThemeBlue       = "Blue"
+	ColorThemeCyan       = "Cyan"
+	ColorThemeDark       = "Dark"
+	ColorThemeLime       = "Lime"
+	ColorThemeNeutral    = "Neutral"
+	ColorThemePink       = "Pink"
+	ColorThemePurple     = "Purple"
+	ColorThemeRed        = "Red"
+	ColorThemeTeal       = "Teal"
+	ColorThemeIndigo     = "Indigo"
+	ColorThemeLightBlue  = "LightBlue"
+	ColorThemeDeepPurple = "DeepPurple"
+	ColorThemeGreen      = "Green"
+	ColorThemeLightGreen = "LightGreen"
+	ColorThemeYellow     = "Yellow"
+	ColorThemeAmber      = "Amber"
+	ColorThemeOrange     = "Orange"
+	ColorThemeDeepOrange = "DeepOrange"
+	ColorThemeBrown      = "Brown"
+	ColorThemeGray       = "Gray"
+	ColorThemeBlueGray   = "BlueGray"
+	ColorThemeOceanGreen = "OceanGreen"
+	ColorThemeGreenOcean = "GreenOcean"
+
+	// Available theme modes.
+	ThemeModeDefault = "Default"
+	ThemeModeLight   = "Light"
+	ThemeModeDark    = "Dark"
+
+	// Available spinners.
+	SpinnerDocument = "Document"
+	SpinnerClassic  = "Classic"
+	SpinnerCubes    = "Cubes"
+	SpinnerBounce   = "Bounce"
+
+	// Available top bars.
+	TopBarDefault  = "Default"
+	TopBarVisible  = "Visible"
+	TopBarOnlyMenu = "OnlyMenu"
+	TopBarHidden   = "Hidden"
+
+	// Available notification setups.
+	NotificationSetupOff       = "off"
+	NotificationSetupSendSms   = "sendSms"
+	NotificationSetupSendEmail = "sendEmail"
+	NotificationSetupSendBoth  = "sendBoth"
+
+	// Available signature methods.
+	SignatureMethodNoBankIDMobile     = "no_bankid_mobile"
+	SignatureMethodNoBankIDNetCentric = "no_bankid_netcentric"
+	SignatureMethodNoBuypass          = "no_buypass"
+	SignatureMethodSeBankID           = "se_bankid"
+	SignatureMethodDkNemID            = "dk_nemid"
+	SignatureMethodFiTupas            = "fi_tupas"
+	SignatureMethodFiMobiilivarmenne  = "fi_mobiilivarmenne"
+	SignatureMethodFiEid              = "fi_eid"
+	SignatureMethodSmsOtp             = "sms_otp"
+	SignatureMethodUnknown            = "unknown"
+
+	// Avalable options for personalInfoOrigin field.
+	PersonalInfoOriginUnknown       = "unknown"
+	PersonalInfoOriginEid           = "eid"
+	PersonalInfoOriginUserFormInput = "userFormInput"
+
+	// Available document statuses.
+	DocumentStatusUnsigned              = "unsigned"
+	DocumentStatusWaitingForAttachments = "waiting_for_attachments"
+	DocumentStatusPartialSigned         = "partialsigned"
+	DocumentStatusSigned                = "signed"
+	DocumentStatusCanceled              = "canceled"
+	DocumentStatusExpired               = "expired"
+
+	// Available file formats.
+	FileFormatUnsigned          = "unsigned"
+	FileFormatNative            = "native"
+	FileFormatStandardPackaging = "standard_packaging"
+	FileFormatPades             = "pades"
+	FileFormatXades             = "xades"
+
+	// Available languages.
+	LanguageEnglish   = "EN"
+	LanguageNorwegian = "NO"
+	LanguageDanish    = "DA"
+	LanguageSweedish  = "SV"
+	LanguageFinnish   = "FI"
+)
+
+// CreateDocumentRequest is the request body used to create a document.
+type CreateDocumentRequest struct {
+	Title          string           `json:"title"`
+	Signers        []*SignerRequest `json:"signers"`
+	DataToSign     *DataToSign      `json:"dataToSign"`
+	ContactDetails *ContactDetails  `json:"contactDetails"`
+	ExternalID     string           `json:"externalId"`
+	Description    string           `json:"description,omitempty"`
+	Notification   *Notification    `json:"notification,omitempty"`
+	Advanced       *Advanced        `json:"advanced,omitempty"`
+}
+
+// SignerRequest is a signer of a document to be created.
+type SignerRequest struct {
+	ExternalSignerID string            `json:"externalSignerId"`
+	RedirectSettings *RedirectSettings `json:"redirectSettings"`
+	SignatureType    *SignatureType    `json:"signatureType"`
+	SignerInfo       *SignerInfo       `json:"signerInfo,omitempty"`
+	Authentication   *Authentication   `json:"authentication,omitempty"`
+	UI               *UI               `json:"ui,omitempty"`
+	Notifications    *Notifications    `json:"notifications,omitempty"`
+	// Order is the signing order. Signers sign in ascending order, and signers with the same order sign in parallel.
+	Order int32 `json:"order,omitempty"`
+	// Required tells whether the document needs the signature of the signer to be signed. Signicat defaults to true.
+	Required *bool `json:"required,omitempty"`
+}
+
+// RedirectSettings is where and how the signer is redirected after signing.
+type RedirectSettings struct {
+	RedirectMode string `json:"redirectMode"`
+	Domain       string `json:"domain,omitempty"`
+	Error        string `json:"error,omitempty"`
+	Cancel       string `json:"cancel,omitempty"`
+	Success      string `json:"success,omitempty"`
+}
+
+// SignatureType is the signature mechanism, and the signature methods the signer can choose from.
+type SignatureType struct {
+	Mechanism        string   `json:"mechanism"`
+	SignatureMethods []string `json:"signatureMethods,omitempty"`
+}
+
+// Authentication is how the signer must authenticate before viewing the document.
+type Authentication struct {
+	Mechanism               string `json:"mechanism,omitempty"`
+	SocialSecurityNumber    string `json:"socialSecurityNumber,omitempty"`
+	SignatureMethodUniqueID string `json:"signatureMethodUniqueId,omitempty"`
+}
+
+// UI is the language and look of the signing page.
+type UI struct {
+	Language string   `json:"language,omitempty"`
+	Styling  *Styling `json:"styling,omitempty"`
+}
+
+// Styling is the look of the signing page.
+type Styling struct {
+	ColorTheme      string `json:"colorTheme,omitempty"`
+	ThemeMode       string `json:"themeMode,omitempty"`
+	Spinner         string `json:"spinner,omitempty"`
+	TopBar          string `json:"topBar,omitempty"`
+	BackgroundColor string `json:"backgroundColor,omitempty"`
+}
+
+// SignerInfo is personal information about a signer.
+type SignerInfo struct {
+	FirstName            string            `json:"firstName,omitempty"`
+	LastName             string            `json:"lastName,omitempty"`
+	Email                string            `json:"email,omitempty"`
+	SocialSecurityNumber string            `json:"socialSecurityNumber,omitempty"`
+	Mobile               *Mobile           `json:"mobile,omitempty"`
+	OrganizationInfo     *OrganizationInfo `json:"organizationInfo,omitempty"`
+}
+
+// Mobile is a mobile phone number.
+type Mobile struct {
+	CountryCode string `json:"countryCode,omitempty"`
+	Number      string `json:"number,omitempty"`
+}
+
+// OrganizationInfo is the organization a signer signs on behalf of.
+type OrganizationInfo struct {
+	OrgNo       string `json:"orgNo,omitempty"`
+	CompanyName string `json:"companyName,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+// DataToSign is the file to be signed.
+type DataToSign struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Base64Content is the file to sign, base64 encoded. Leave it empty when FileID or Content is set.
+	Base64Content string `json:"base64Content,omitempty"`
+	FileName      string `json:"fileName"`
+	ConvertToPDF  bool   `json:"convertToPdf,omitempty"`
+	// FileID references a file uploaded with UploadFile, instead of sending the file in the request.
+	FileID string `json:"fileId,omitempty"`
+	// Content is the file to sign. When set, it is base64 encoded while the request is sent, and used instead of Base64Content.
+	Content io.Reader `json:"-"`
+}
+
+// ContactDetails is the contact details shown to the signers.
+type ContactDetails struct {
+	Name  string `json:"name,omitempty"`
+	Phone string `json:"phone,omitempty"`
+	Email string `json:"email"`
+	URL   string `json:"url,omitempty"`
+}
+
+// Notification is the notifications sent for a document.
+type Notification struct {
+	SignRequest      *SignRequest      `json:"signRequest,omitempty"`
+	Reminder         *Reminder         `json:"reminder,omitempty"`
+	SignatureReceipt *SignatureReceipt `json:"signatureReceipt,omitempty"`
+	FinalReceipt     *FinalReceipt     `json:"finalReceipt,omitempty"`
+	CanceledReceipt  *CanceledReceipt  `json:"canceledReceipt,omitempty"`
+	ExpiredReceipt   *ExpiredReceipt   `json:"expiredReceipt,omitempty"`
+}
+
+// SignRequest is the notification sent to request a signature.
+type SignRequest struct {
+	IncludeOriginalFile bool     `json:"includeOriginalFile,omitempty"`
+	Email               []*Email `json:"email,omitempty"`
+	Sms                 []*Sms   `json:"sms,omitempty"`
+}
+
+// Email is an email text in one language.
+type Email struct {
+	Language   string `json:"language"`
+	Subject    string `json:"subject,omitempty"`
+	Text       string `json:"text,omitempty"`
+	SenderName string `json:"senderName,omitempty"`
+}
+
+// Sms is an sms text in one language.
+type Sms struct {
+	Language string `json:"language"`
+	Text     string `json:"text,omitempty"`
+	Sender   string `json:"sender,omitempty"`
+}
+
+// Advanced is advanced settings for a document.
+type Advanced struct {
+	TimeToLive *TimeToLive `json:"timeToLive,omitempty"`
+}
+
+// TimeToLive is how long a document can be signed, and how long it is kept.
+type TimeToLive struct {
+	Deadline         *time.Time `json:"deadline,omitempty"`
+	DeleteAfterHours int32      `json:"deleteAfterHours,omitempty"`
+}
+
+// Reminder is the reminders sent to signers who haven't signed.
+type Reminder struct {
+	ChronSchedule string   `json:"chronSchedule"`
+	MaxReminders  int32    `json:"maxReminders,omitempty"`
+	Email         []*Email `json:"email,omitempty"`
+	Sms           []*Sms   `json:"sms,omitempty"`
+}
+
+// SignatureReceipt is the receipt sent to a signer after signing.
+type SignatureReceipt struct {
+	Email []*Email `json:"email,omitempty"`
+	Sms   []*Sms   `json:"sms,omitempty"`
+}
+
+// FinalReceipt is the receipt sent when all signers have signed.
+type FinalReceipt struct {
+	AdditionalRecipients []*AdditionalRecipient `json:"additionalRecipients,omitempty"`
+	IncludeSignedFile    bool                   `json:"includeSignedFile,omitempty"`
+	Email                []*Email               `json:"email,omitempty"`
+	Sms                  []*Sms                 `json:"sms,omitempty"`
+}
+
+// AdditionalRecipient is a recipient of the final receipt who isn't a signer.
+type AdditionalRecipient struct {
+	Language          string            `json:"language,omitempty"`
+	Email             string            `json:"email"`
+	CustomMergeFields map[string]string `json:"customMergeFields,omitempty"`
+}
+
+// CanceledReceipt is the notification sent when a document is canceled.
+type CanceledReceipt struct {
+	Email []*Email `json:"email,omitempty"`
+	Sms   []*Sms   `json:"sms,omitempty"`
+}
+
+// ExpiredReceipt is the notification sent when a document expires.
+type ExpiredReceipt struct {
+	Email []*Email `json:"email,omitempty"`
+	Sms   []*Sms   `json:"sms,omitempty"`
+}
+
+// Notifications is the notification settings of a signer.
+type Notifications struct {
+	Setup *Setup `json:"setup,omitempty"`
+}
+
+// Setup is which channels are used for each notification sent to a signer.
+type Setup struct {
+	Request          string `json:"request,omitempty"`
+	Reminder         string `json:"reminder,omitempty"`
+	SignatureReceipt string `json:"signatureReceipt,omitempty"`
+	FinalReceipt     string `json:"finalReceipt,omitempty"`
+	Canceled         string `json:"canceled,omitempty"`
+	Expired          string `json:"expired,omitempty"`
+}
+
+// Document is a document.
+type Document struct {
+	DocumentID     string            `json:"documentId,omitempty"`
+	Signers        []*SignerResponse `json:"signers,omitempty"`
+	Status         *Status           `json:"status,omitempty"`
+	Title          string            `json:"title,omitempty"`
+	Description    string            `json:"description,omitempty"`
+	ExternalID     string            `json:"externalId,omitempty"`
+	DataToSign     *DataToSign       `json:"dataToSign,omitempty"`
+	ContactDetails *ContactDetails   `json:"contactDetails,omitempty"`
+	Advanced       *Advanced         `json:"advanced,omitempty"`
+	// ContentDigest is the digest of the file to sign, computed when creating the document. Signicat doesn't store it.
+	ContentDigest *ContentDigest `json:"contentDigest,omitempty"`
+	// Extra holds the fields in the response which are not modelled.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// SignerResponse is a signer of a document.
+type SignerResponse struct {
+	ID                string             `json:"id,omitempty"`
+	URL               string             `json:"url,omitempty"`
+	DocumentSignature *DocumentSignature `json:"documentSignature,omitempty"`
+	ExternalSignerID  string             `json:"externalSignerId,omitempty"`
+	RedirectSettings  *RedirectSettings  `json:"redirectSettings,omitempty"`
+	SignatureType     *SignatureType     `json:"signatureType,omitempty"`
+	SignerInfo        *SignerInfo        `json:"signerInfo,omitempty"`
+	Notifications     *Notifications     `json:"notifications,omitempty"`
+	Order             int32              `json:"order,omitempty"`
+	// Required tells whether the document needs the signature of the signer to be signed. Absent means true, the Signicat default.
+	Required                *bool      `json:"required,omitempty"`
+	SignURLExpires          *time.Time `json:"signUrlExpires,omitempty"`
+	GetSocialSecurityNumber bool       `json:"getSocialSecurityNumber,omitempty"`
+	// Extra holds the fields in the response which are not modelled.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// DocumentSignature is the signature of a signer who has signed.
+type DocumentSignature struct {
+	SignatureMethod         string                `json:"signatureMethod"`
+	FullName                string                `json:"fullName,omitempty"`
+	FirstName               string                `json:"firstName,omitempty"`
+	LastName                string                `json:"lastName,omitempty"`
+	MiddleName              string                `json:"middleName,omitempty"`
+	SignedTime              *time.Time            `json:"signedTime,omitempty"`
+	DateOfBirth             string                `json:"dateOfBirth,omitempty"`
+	SignatureMethodUniqueID string                `json:"signatureMethodUniqueId,omitempty"`
+	SocialSecurityNumber    *SocialSecurityNumber `json:"socialSecurityNumber,omitempty"`
+	ClientIP                string                `json:"clientIp,omitempty"`
+	Mechanism               string                `json:"mechanism,omitempty"`
+	PersonalInfoOrigin      string                `json:"personalInfoOrigin,omitempty"`
+}
+
+// SocialSecurityNumber is a national identity number and the country which issued it.
+type SocialSecurityNumber struct {
+	Value       string `json:"value,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+// Status is the status of a document.
+type Status struct {
+	DocumentStatus    string   `json:"documentStatus,omitempty"`
+	CompletedPackages []string `json:"completedPackages,omitempty"`
+	// Extra holds the fields in the response which are not modelled.
+	Extra map[string]json.RawMessage `json:"-"`
+}
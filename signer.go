@@ -0,0 +1,141 @@
+package signicat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RetrieveSigner retrieves a single signer of a document.
+func (s *SignatureService) RetrieveSigner(ctx context.Context, documentID, signerID string) (*SignerResponse, error) {
+	u := fmt.Sprintf("/signature/documents/%s/signers/%s", documentID, signerID)
+	req, err := s.client.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(SignerResponse)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// RenewSignerURL gives the signer a new sign URL with a new expiry. The old URL stops working.
+func (s *SignatureService) RenewSignerURL(ctx context.Context, documentID, signerID string) (*SignerResponse, error) {
+	u := fmt.Sprintf("/signature/documents/%s/signers/%s/renew", documentID, signerID)
+	req, err := s.client.NewRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := new(SignerResponse)
+	if err := s.client.Do(ctx, req, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// RenewExpiringSignerURLs renews the sign URL of every signer of document who hasn't signed, and whose URL expires before the given
+// time. The renewed signers replace the old ones in document.Signers, and are returned. A *StatusError is returned without renewing
+// any URLs if document.Status tells that the document is already signed, canceled or expired.
+func (s *SignatureService) RenewExpiringSignerURLs(ctx context.Context, document *Document, before time.Time) ([]*SignerResponse, error) {
+	if document.Status != nil && document.Status.IsTerminal() {
+		status := document.Status.DocumentStatus
+		return nil, &StatusError{Operation: "renew sign URLs of", DocumentID: document.DocumentID, DocumentStatus: status}
+	}
+
+	var renewed []*SignerResponse
+	for i, signer := range document.Signers {
+		if !signer.SignURLExpiresBefore(before) {
+			continue
+		}
+
+		r, err := s.RenewSignerURL(ctx, document.DocumentID, signer.ID)
+		if err != nil {
+			return renewed, err
+		}
+		document.Signers[i] = r
+		renewed = append(renewed, r)
+	}
+
+	return renewed, nil
+}
+
+// SignURLExpiresBefore reports whether the signer hasn't signed yet, and the sign URL expires before t.
+func (s *SignerResponse) SignURLExpiresBefore(t time.Time) bool {
+	return s.DocumentSignature == nil && s.SignURLExpires != nil && s.SignURLExpires.Before(t)
+}
+
+// IsRequired reports whether the document needs the signature of the signer. A signer without Required set is required, which
+// is the Signicat default.
+func (s *SignerResponse) IsRequired() bool {
+	return s.Required == nil || *s.Required
+}
+
+// SigningState is who can sign a document at a point in time. Every signer is in exactly one of Signed, Eligible and Blocked.
+type SigningState struct {
+	// Signed are the signers who have signed.
+	Signed []*SignerResponse
+	// Eligible are the signers who can sign now.
+	Eligible []*SignerResponse
+	// Blocked are the signers who can't sign now, either because they wait for signers earlier in the signing order, because
+	// their sign URL has expired or because the document can't be signed anymore.
+	Blocked []*SignerResponse
+	// CanComplete tells whether the document is signed, or can still become signed without renewing any sign URLs. It is false
+	// if the sign URL of a required signer who hasn't signed has expired, see RenewExpiringSignerURLs. Signers who reject are
+	// not reported by the API, so they are only taken into account once the document is canceled.
+	CanComplete bool
+}
+
+// SigningState computes who can sign the document now. See SigningStateAt.
+func (d *Document) SigningState() *SigningState {
+	return d.SigningStateAt(time.Now())
+}
+
+// SigningStateAt computes who can sign the document at t, based on the signing order, which signers are required and when their
+// sign URLs expire. A signer is eligible when every unsigned required signer has the same or a later order, and the sign URL of
+// the signer hasn't expired. Once every required signer has signed, the document is complete and the remaining optional signers
+// are blocked. See IsRequired.
+func (d *Document) SigningStateAt(t time.Time) *SigningState {
+	state := &SigningState{}
+	expired := func(signer *SignerResponse) bool {
+		return signer.SignURLExpires != nil && !signer.SignURLExpires.After(t)
+	}
+
+	// The order of the next required signer decides who can sign. Without one, nothing is left to sign.
+	next, remaining, stuck := int32(0), false, false
+	for _, signer := range d.Signers {
+		if signer.DocumentSignature != nil || !signer.IsRequired() {
+			continue
+		}
+		stuck = stuck || expired(signer)
+		if !remaining || signer.Order < next {
+			next, remaining = signer.Order, true
+		}
+	}
+
+	open := remaining && (d.Status == nil || !d.Status.IsTerminal())
+
+	for _, signer := range d.Signers {
+		switch {
+		case signer.DocumentSignature != nil:
+			state.Signed = append(state.Signed, signer)
+		case open && signer.Order <= next && !expired(signer):
+			state.Eligible = append(state.Eligible, signer)
+		default:
+			state.Blocked = append(state.Blocked, signer)
+		}
+	}
+
+	if d.Status != nil && d.Status.IsTerminal() {
+		state.CanComplete = d.Status.DocumentStatus == DocumentStatusSigned
+	} else {
+		state.CanComplete = len(d.Signers) > 0 && !stuck
+	}
+
+	return state
+}
@@ -0,0 +1,219 @@
+package signicat
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSignatureService_RetrieveSigner(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, "/signature/documents/someDocumentId/signers/someSignerId", req.URL.Path)
+		if _, err := io.WriteString(res, `{"id":"someSignerId","url":"https://example.com"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	signer, err := client.Signature.RetrieveSigner(context.Background(), "someDocumentId", "someSignerId")
+	assert.NoError(t, err)
+	assert.Equal(t, "someSignerId", signer.ID)
+}
+
+func TestSignatureService_RenewSignerURL(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/signature/documents/someDocumentId/signers/someSignerId/renew", req.URL.Path)
+		if _, err := io.WriteString(res, `{"id":"someSignerId","url":"https://example.com/new","signUrlExpires":"2020-07-01T00:00:00Z"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	signer, err := client.Signature.RenewSignerURL(context.Background(), "someDocumentId", "someSignerId")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/new", signer.URL)
+	assert.Equal(t, time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC), *signer.SignURLExpires)
+}
+
+func TestSignatureService_RenewExpiringSignerURLs(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	var renewedIDs []string
+	mux.HandleFunc("/signature/documents/someDocumentId/signers/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		renewedIDs = append(renewedIDs, req.URL.Path)
+		if _, err := io.WriteString(res, `{"id":"expiring","url":"https://example.com/new"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	now := time.Date(2020, 6, 26, 12, 0, 0, 0, time.UTC)
+	soon, later := now.Add(time.Hour), now.Add(48*time.Hour)
+	document := &Document{
+		DocumentID: "someDocumentId",
+		Signers: []*SignerResponse{
+			{ID: "expiring", URL: "https://example.com/old", SignURLExpires: &soon},
+			{ID: "valid", SignURLExpires: &later},
+			{ID: "signed", SignURLExpires: &soon, DocumentSignature: &DocumentSignature{}},
+			{ID: "unknown"},
+		},
+	}
+
+	renewed, err := client.Signature.RenewExpiringSignerURLs(context.Background(), document, now.Add(24*time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, renewed, 1)
+	assert.Equal(t, []string{"/signature/documents/someDocumentId/signers/expiring/renew"}, renewedIDs)
+	assert.Equal(t, "https://example.com/new", document.Signers[0].URL)
+	assert.Equal(t, "valid", document.Signers[1].ID)
+}
+
+func TestSignatureService_RenewExpiringSignerURLs_Terminal(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/signature/documents/someDocumentId/signers/", func(res http.ResponseWriter, req *http.Request) {
+		t.Fatal("no URLs should be renewed")
+	})
+
+	now := time.Date(2020, 6, 26, 12, 0, 0, 0, time.UTC)
+	soon := now.Add(time.Hour)
+	document := &Document{
+		DocumentID: "someDocumentId",
+		Status:     &Status{DocumentStatus: DocumentStatusCanceled},
+		Signers:    []*SignerResponse{{ID: "expiring", SignURLExpires: &soon}},
+	}
+
+	renewed, err := client.Signature.RenewExpiringSignerURLs(context.Background(), document, now.Add(24*time.Hour))
+	assert.Empty(t, renewed)
+	assert.Equal(t, &StatusError{Operation: "renew sign URLs of", DocumentID: "someDocumentId", DocumentStatus: DocumentStatusCanceled}, err)
+	assert.EqualError(t, err, "can't renew sign URLs of document someDocumentId with status canceled")
+}
+
+func TestSignerResponse_IsRequired(t *testing.T) {
+	yes, no := true, false
+	assert.True(t, (&SignerResponse{}).IsRequired())
+	assert.True(t, (&SignerResponse{Required: &yes}).IsRequired())
+	assert.False(t, (&SignerResponse{Required: &no}).IsRequired())
+}
+
+func TestDocument_SigningState(t *testing.T) {
+	signed := &DocumentSignature{}
+	optional := false
+	now := time.Date(2020, 6, 26, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	ids := func(signers []*SignerResponse) []string {
+		var ids []string
+		for _, s := range signers {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name             string
+		document         *Document
+		expectedSigned   []string
+		expectedEligible []string
+		expectedBlocked  []string
+		expectedComplete bool
+	}{
+		{
+			name: "parallel",
+			document: &Document{Signers: []*SignerResponse{
+				{ID: "ceo"},
+				{ID: "cfo", DocumentSignature: signed},
+			}},
+			expectedSigned:   []string{"cfo"},
+			expectedEligible: []string{"ceo"},
+			expectedComplete: true,
+		},
+		{
+			name: "sequential",
+			document: &Document{Signers: []*SignerResponse{
+				{ID: "ceo", Order: 1},
+				{ID: "cfo", Order: 2},
+				{ID: "witness", Order: 2, Required: &optional},
+			}},
+			expectedEligible: []string{"ceo"},
+			expectedBlocked:  []string{"cfo", "witness"},
+			expectedComplete: true,
+		},
+		{
+			name: "optional signer doesn't block",
+			document: &Document{Signers: []*SignerResponse{
+				{ID: "witness", Order: 1, Required: &optional},
+				{ID: "ceo", Order: 2},
+				{ID: "cfo", Order: 3},
+			}},
+			expectedEligible: []string{"witness", "ceo"},
+			expectedBlocked:  []string{"cfo"},
+			expectedComplete: true,
+		},
+		{
+			name: "required signers done",
+			document: &Document{Signers: []*SignerResponse{
+				{ID: "ceo", Order: 1, DocumentSignature: signed},
+				{ID: "witness", Order: 2, Required: &optional},
+			}},
+			expectedSigned:   []string{"ceo"},
+			expectedBlocked:  []string{"witness"},
+			expectedComplete: true,
+		},
+		{
+			name: "only optional signers",
+			document: &Document{Signers: []*SignerResponse{
+				{ID: "witness", Order: 1, Required: &optional},
+				{ID: "notary", Order: 2, Required: &optional},
+			}},
+			expectedBlocked:  []string{"witness", "notary"},
+			expectedComplete: true,
+		},
+		{
+			name: "canceled",
+			document: &Document{
+				Status:  &Status{DocumentStatus: DocumentStatusCanceled},
+				Signers: []*SignerResponse{{ID: "ceo"}},
+			},
+			expectedBlocked:  []string{"ceo"},
+			expectedComplete: false,
+		},
+		{
+			name: "required signer's URL expired",
+			document: &Document{Signers: []*SignerResponse{
+				{ID: "ceo", Order: 1, SignURLExpires: &past},
+				{ID: "cfo", Order: 1, SignURLExpires: &future},
+			}},
+			expectedEligible: []string{"cfo"},
+			expectedBlocked:  []string{"ceo"},
+			expectedComplete: false,
+		},
+		{
+			name: "optional signer's URL expired",
+			document: &Document{Signers: []*SignerResponse{
+				{ID: "ceo", Order: 1, SignURLExpires: &future},
+				{ID: "witness", Order: 1, Required: &optional, SignURLExpires: &past},
+			}},
+			expectedEligible: []string{"ceo"},
+			expectedBlocked:  []string{"witness"},
+			expectedComplete: true,
+		},
+	}
+
+	for _, test := range tests {
+		state := test.document.SigningStateAt(now)
+		assert.Equal(t, test.expectedSigned, ids(state.Signed), test.name)
+		assert.Equal(t, test.expectedEligible, ids(state.Eligible), test.name)
+		assert.Equal(t, test.expectedBlocked, ids(state.Blocked), test.name)
+		assert.Equal(t, test.expectedComplete, state.CanComplete, test.name)
+	}
+}
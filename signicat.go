@@ -16,21 +16,34 @@ const (
 
 // A Client manages communication with the Signicat API.
 type Client struct {
-	client  *http.Client
-	baseURL *url.URL
+	client     *http.Client
+	baseURL    *url.URL
+	decodeMode DecodeMode
 
 	common service
 
-	Signature *SignatureService
+	Account        *AccountService
+	Identification *IdentificationService
+	Signature      *SignatureService
 }
 
 type service struct {
 	client *Client
 }
 
+// ClientOption configures optional behaviour of a Client.
+type ClientOption func(*Client)
+
+// WithDecodeMode sets how fields in responses which are not modelled are handled. Default is DecodeLenient.
+func WithDecodeMode(mode DecodeMode) ClientOption {
+	return func(c *Client) {
+		c.decodeMode = mode
+	}
+}
+
 // NewClient returns a new client with the default base url.
-func NewClient(httpClient *http.Client) *Client {
-	client, err := NewClientWithURL(httpClient, defaultBaseURL)
+func NewClient(httpClient *http.Client, opts ...ClientOption) *Client {
+	client, err := NewClientWithURL(httpClient, defaultBaseURL, opts...)
 	if err != nil {
 		panic("unable to initiate default client. This should not happen")
 	}
@@ -40,7 +53,7 @@ func NewClient(httpClient *http.Client) *Client {
 
 // NewClientWithURL returns a new Signicat API client. To use API methods which require authentication, provide an http.Client
 // that will perform the authentication for you. Most likelely you want to use Oauth2 and the golang.org/x/oauth2 package.
-func NewClientWithURL(httpClient *http.Client, baseURL string) (*Client, error) {
+func NewClientWithURL(httpClient *http.Client, baseURL string, opts ...ClientOption) (*Client, error) {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
@@ -55,19 +68,48 @@ func NewClientWithURL(httpClient *http.Client, baseURL string) (*Client, error)
 		baseURL: u,
 	}
 
+	for _, opt := range opts {
+		opt(c)
+	}
+
 	c.common.client = c
+	c.Account = (*AccountService)(&c.common)
+	c.Identification = (*IdentificationService)(&c.common)
 	c.Signature = (*SignatureService)(&c.common)
 
 	return c, nil
 }
 
 // NewRequest creates a new API request with the provided http method, body and with path which is the clients base url + relativeUrl.
+// The body is JSON encoded, unless it is an io.Reader.
 func (c *Client) NewRequest(method, relativeURL string, body interface{}) (*http.Request, error) {
 	u, err := c.baseURL.Parse(relativeURL)
 	if err != nil {
 		return nil, err
 	}
 
+	// Requests with a file to stream are encoded while they are sent, instead of being held in memory.
+	if streamedContent(body) != nil {
+		buf, length, err := newStreamingBody(body.(*CreateDocumentRequest))
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequest(method, u.String(), buf)
+		if err != nil {
+			return nil, err
+		}
+		req.ContentLength = length
+		req.Header.Set("Content-Type", "application/json")
+
+		return req, nil
+	}
+
+	// Readers are sent as is. The caller sets the Content-Type.
+	if r, ok := body.(io.Reader); ok {
+		return http.NewRequest(method, u.String(), r)
+	}
+
 	var buf io.ReadWriter
 	if body != nil {
 		buf = new(bytes.Buffer)
@@ -105,9 +147,9 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) error
 	}
 	defer res.Body.Close()
 
-	// TODO: Better error handling. Make a custom error type with info?
+	// TODO: Better error handling. Add the error details from the body to ErrorResponse?
 	if res.StatusCode < 200 || res.StatusCode > 299 {
-		return fmt.Errorf("received response with http code: %d", res.StatusCode)
+		return &ErrorResponse{StatusCode: res.StatusCode}
 	}
 
 	if v != nil {
@@ -117,8 +159,7 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) error
 				return err
 			}
 		} else {
-			// Ignore EOF errors caused by empty response body.
-			if err = json.NewDecoder(res.Body).Decode(v); err != nil && err != io.EOF {
+			if err := c.decode(res.Body, v); err != nil {
 				return err
 			}
 		}
@@ -126,3 +167,12 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) error
 
 	return nil
 }
+
+// ErrorResponse is returned when the API responds with a non 2xx status code.
+type ErrorResponse struct {
+	StatusCode int
+}
+
+func (e *ErrorResponse) Error() string {
+	return fmt.Sprintf("received response with http code: %d", e.StatusCode)
+}
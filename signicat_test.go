@@ -63,6 +63,24 @@ func TestSignatureService_RetrieveDocument(t *testing.T) {
 	assert.Equal(t, "someDocumentId", document.DocumentID)
 }
 
+func TestSignatureService_ListDocuments(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, "/signature/documents", req.URL.Path)
+		assert.Equal(t, "signed", req.URL.Query().Get("status"))
+		if _, err := io.WriteString(res, `[{"documentId":"first"},{"documentId":"second"}]`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	documents, err := client.Signature.ListDocuments(context.Background(), &ListDocumentsOptions{Status: DocumentStatusSigned})
+	assert.NoError(t, err)
+	assert.Len(t, documents, 2)
+}
+
 func TestSignatureService_RetrieveDocumentStatus(t *testing.T) {
 	client, mux, teardown := setup()
 	defer teardown()
@@ -0,0 +1,172 @@
+package signicat
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// contentPlaceholder stands in for the base64 content while the rest of the request is encoded. The encoded request is split
+// around it, and the content is streamed in between.
+const contentPlaceholder = "signicat-streamed-content-placeholder"
+
+// streamedContent returns the reader of the file to stream in body, or nil if body doesn't stream a file.
+func streamedContent(body interface{}) io.Reader {
+	createReq, ok := body.(*CreateDocumentRequest)
+	if !ok || createReq == nil || createReq.DataToSign == nil {
+		return nil
+	}
+
+	return createReq.DataToSign.Content
+}
+
+// newStreamingBody returns the JSON encoding of createReq, where DataToSign.Content is base64 encoded as it is read. Only the
+// rest of the request is held in memory, so memory usage doesn't depend on the size of the file. The returned length is -1 if
+// the size of the content is unknown.
+func newStreamingBody(createReq *CreateDocumentRequest) (io.ReadCloser, int64, error) {
+	r := *createReq
+	dataToSign := *r.DataToSign
+	dataToSign.Base64Content = contentPlaceholder
+	r.DataToSign = &dataToSign
+
+	b, err := json.Marshal(&r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	parts := bytes.Split(b, []byte(contentPlaceholder))
+	if len(parts) != 2 {
+		return nil, 0, errors.New("request can't be streamed, the content placeholder is used elsewhere in the request")
+	}
+
+	body := &streamingBody{prefix: parts[0], content: createReq.DataToSign.Content, suffix: parts[1]}
+
+	length := int64(-1)
+	if n, ok := remaining(body.content); ok {
+		length = int64(len(body.prefix)) + (n+2)/3*4 + int64(len(body.suffix))
+	}
+
+	return body, length, nil
+}
+
+// streamingBody writes the request through a pipe. Encoding starts on the first read, so no goroutine is left behind if the
+// request is never sent.
+type streamingBody struct {
+	prefix  []byte
+	content io.Reader
+	suffix  []byte
+
+	once sync.Once
+	pr   *io.PipeReader
+	// done is closed when the content is no longer read.
+	done chan struct{}
+}
+
+func (b *streamingBody) Read(p []byte) (int, error) {
+	b.once.Do(b.start)
+	return b.pr.Read(p)
+}
+
+func (b *streamingBody) Close() error {
+	b.once.Do(b.start)
+	return b.pr.Close()
+}
+
+func (b *streamingBody) start() {
+	pr, pw := io.Pipe()
+	b.pr = pr
+	b.done = make(chan struct{})
+
+	go func() {
+		defer close(b.done)
+		pw.CloseWithError(b.write(pw))
+	}()
+}
+
+// finishStreaming stops streaming the body of req, if it streams a file, and waits until the file is no longer read. The server
+// can respond before it has read the whole request, and the file must not be read after the response is handled.
+func finishStreaming(req *http.Request) {
+	b, ok := req.Body.(*streamingBody)
+	if !ok {
+		return
+	}
+
+	b.Close()
+	<-b.done
+}
+
+func (b *streamingBody) write(w io.Writer) error {
+	if _, err := w.Write(b.prefix); err != nil {
+		return err
+	}
+
+	enc := base64.NewEncoder(base64.StdEncoding, w)
+	if _, err := io.Copy(enc, b.content); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(b.suffix)
+	return err
+}
+
+// remaining returns the number of bytes left in r, if it can be known without reading it.
+func remaining(r io.Reader) (int64, bool) {
+	if d, ok := r.(*digestReader); ok {
+		return remaining(d.r)
+	}
+
+	if l, ok := r.(interface{ Len() int }); ok {
+		return int64(l.Len()), true
+	}
+
+	if s, ok := r.(io.Seeker); ok {
+		cur, err := s.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		end, err := s.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, false
+		}
+		if _, err := s.Seek(cur, io.SeekStart); err != nil {
+			return 0, false
+		}
+		return end - cur, true
+	}
+
+	return 0, false
+}
+
+// contentOffset returns the position of the streamed content of createReq, to rewind it to if the request is sent again. It
+// reports false if the content can't be read again.
+func contentOffset(createReq *CreateDocumentRequest) (int64, bool) {
+	content := streamedContent(createReq)
+	if content == nil {
+		return 0, true
+	}
+
+	s, ok := content.(io.Seeker)
+	if !ok {
+		return 0, false
+	}
+	offset, err := s.Seek(0, io.SeekCurrent)
+
+	return offset, err == nil
+}
+
+// rewind moves the streamed content of createReq back to offset.
+func rewind(createReq *CreateDocumentRequest, offset int64) error {
+	if s, ok := streamedContent(createReq).(io.Seeker); ok {
+		_, err := s.Seek(offset, io.SeekStart)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package signicat
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// zeroReader reads n zero bytes without allocating them up front.
+type zeroReader struct {
+	n int64
+}
+
+func (z *zeroReader) Read(p []byte) (int, error) {
+	if z.n <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > z.n {
+		p = p[:z.n]
+	}
+	for i := range p {
+		p[i] = 0
+	}
+	z.n -= int64(len(p))
+
+	return len(p), nil
+}
+
+// countingReader counts the bytes read from r. The count can be read while r is being read from another goroutine.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	atomic.AddInt64(&c.n, int64(n))
+
+	return n, err
+}
+
+func TestSignatureService_CreateDocument_Streaming(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	content := []byte("%PDF-1.4\n%streamed")
+	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+		b, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(b)), req.ContentLength)
+
+		var body CreateDocumentRequest
+		assert.NoError(t, json.Unmarshal(b, &body))
+		assert.Equal(t, "Contract", body.Title)
+		assert.Equal(t, "contract.pdf", body.DataToSign.FileName)
+		assert.Equal(t, base64.StdEncoding.EncodeToString(content), body.DataToSign.Base64Content)
+
+		if _, err := io.WriteString(res, `{"documentId":"someDocumentId"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	document, err := client.Signature.CreateDocument(context.Background(), &CreateDocumentRequest{
+		Title:      "Contract",
+		DataToSign: &DataToSign{FileName: "contract.pdf", Content: bytes.NewReader(content)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "someDocumentId", document.DocumentID)
+}
+
+func TestSignatureService_CreateDocumentIdempotent_Streaming(t *testing.T) {
+	client, mux, teardown := setup()
+	defer teardown()
+
+	content := "%PDF-1.4\n%streamed"
+	attempts := 0
+	mux.HandleFunc("/signature/documents", func(res http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet {
+			if _, err := io.WriteString(res, `[]`); err != nil {
+				t.Fatal(err)
+			}
+			return
+		}
+
+		var body CreateDocumentRequest
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(content)), body.DataToSign.Base64Content)
+
+		attempts++
+		if attempts == 1 {
+			res.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if _, err := io.WriteString(res, `{"documentId":"someDocumentId"}`); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	// Without an external ID there is no key to deduplicate on.
+	_, err := client.Signature.CreateDocumentIdempotent(context.Background(), &CreateDocumentRequest{
+		DataToSign: &DataToSign{Content: strings.NewReader(content)},
+	})
+	assert.Error(t, err)
+
+	document, err := client.Signature.CreateDocumentIdempotent(context.Background(), &CreateDocumentRequest{
+		ExternalID: "someExternalId",
+		DataToSign: &DataToSign{Content: strings.NewReader(content)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "someDocumentId", document.DocumentID)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestNewRequest_StreamingMemory(t *testing.T) {
+	const size = 32 << 20
+
+	content := &countingReader{r: &zeroReader{n: size}}
+	client := NewClient(nil)
+	req, err := client.NewRequest(http.MethodPost, "/signature/documents", &CreateDocumentRequest{
+		DataToSign: &DataToSign{Content: content},
+	})
+	assert.NoError(t, err)
+
+	// Buffering would read the whole file before the body could be read. Streaming only reads ahead of the body by the size of
+	// the buffers in between.
+	var n, ahead int64
+	buf := make([]byte, 32<<10)
+	for {
+		m, err := req.Body.Read(buf)
+		n += int64(m)
+		if d := atomic.LoadInt64(&content.n) - n*3/4; d > ahead {
+			ahead = d
+		}
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int64(size), atomic.LoadInt64(&content.n))
+	assert.True(t, n > size*4/3)
+	assert.True(t, ahead < 1<<20, "read %d bytes ahead of the body", ahead)
+}
+
+func benchmarkNewRequest(b *testing.B, size int64, streaming bool) {
+	client := NewClient(nil)
+	b.SetBytes(size)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		dataToSign := &DataToSign{FileName: "contract.pdf"}
+		if streaming {
+			dataToSign.Content = &zeroReader{n: size}
+		} else {
+			content, err := ioutil.ReadAll(&zeroReader{n: size})
+			if err != nil {
+				b.Fatal(err)
+			}
+			dataToSign.Base64Content = base64.StdEncoding.EncodeToString(content)
+		}
+
+		req, err := client.NewRequest(http.MethodPost, "/signature/documents", &CreateDocumentRequest{DataToSign: dataToSign})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.Copy(ioutil.Discard, req.Body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNewRequest_Buffered1MB(b *testing.B)   { benchmarkNewRequest(b, 1<<20, false) }
+func BenchmarkNewRequest_Buffered40MB(b *testing.B)  { benchmarkNewRequest(b, 40<<20, false) }
+func BenchmarkNewRequest_Streaming1MB(b *testing.B)  { benchmarkNewRequest(b, 1<<20, true) }
+func BenchmarkNewRequest_Streaming40MB(b *testing.B) { benchmarkNewRequest(b, 40<<20, true) }
@@ -0,0 +1,93 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/larwef/signicat"
+)
+
+// Available sms encodings.
+const (
+	EncodingGSM7 = "GSM-7"
+	EncodingUCS2 = "UCS-2"
+)
+
+// Characters in the GSM 03.38 basic character set and its extension table. Extension characters take up two characters in a
+// message.
+const (
+	gsm7Basic     = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7Extension = "\f^{}\\[~]|€"
+)
+
+// SmsInfo describes how a text is sent as sms.
+type SmsInfo struct {
+	Language string
+	Encoding string
+	// Length is the number of characters in the encoding, counting GSM-7 extension characters twice.
+	Length   int
+	Segments int
+}
+
+// MeasureSms returns the encoding, length and number of segments text will be sent as.
+func MeasureSms(text string) SmsInfo {
+	length, gsm7 := 0, true
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune(gsm7Basic, r):
+			length++
+		case strings.ContainsRune(gsm7Extension, r):
+			length += 2
+		default:
+			gsm7 = false
+		}
+	}
+
+	if gsm7 {
+		return SmsInfo{Encoding: EncodingGSM7, Length: length, Segments: segments(length, 160, 153)}
+	}
+
+	length = len(utf16.Encode([]rune(text)))
+	return SmsInfo{Encoding: EncodingUCS2, Length: length, Segments: segments(length, 70, 67)}
+}
+
+// CheckSms renders each sms with values and measures the result. Returns the measurements per language, and an error if any
+// sms can't be rendered or needs more than maxSegments segments. A maxSegments of 0 means no limit.
+func CheckSms(sms []*signicat.Sms, values map[string]string, maxSegments int) ([]SmsInfo, error) {
+	var infos []SmsInfo
+	var problems []string
+
+	for _, s := range sms {
+		text, err := Render(s.Text, values)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("sms[%s]: %v", s.Language, err))
+			continue
+		}
+
+		info := MeasureSms(text)
+		info.Language = s.Language
+		infos = append(infos, info)
+
+		if maxSegments > 0 && info.Segments > maxSegments {
+			problems = append(problems, fmt.Sprintf("sms[%s]: %d segments exceeds the limit of %d", s.Language, info.Segments, maxSegments))
+		}
+	}
+
+	if len(problems) > 0 {
+		return infos, fmt.Errorf("invalid sms: %s", strings.Join(problems, "; "))
+	}
+
+	return infos, nil
+}
+
+func segments(length, single, multi int) int {
+	if length == 0 {
+		return 0
+	}
+	if length <= single {
+		return 1
+	}
+
+	return (length + multi - 1) / multi
+}
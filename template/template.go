@@ -0,0 +1,285 @@
+// Package template parses, validates and renders the merge fields used in Signicat notification texts.
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/larwef/signicat"
+)
+
+// SampleURL is used in place of the sign URL when rendering previews. It has roughly the length of the short URLs Signicat uses
+// in notifications, which makes sms segment counts of previews realistic.
+const SampleURL = "https://sign.idfy.io/s/aBcDeFgHiJ"
+
+// MergeFields lists the merge fields Signicat knows about.
+var MergeFields = []string{
+	signicat.MergeFieldDocumentTitle,
+	signicat.MergeFieldDocumentDescription,
+	signicat.MergeFieldSignableDocumetTitles,
+	signicat.MergeFieldReadOnlyDocumentTitles,
+	signicat.MergeFieldDeadline,
+	signicat.MergeFieldSignedTime,
+	signicat.MergeFieldSignedDate,
+	signicat.MergeFieldSignedName,
+	signicat.MergeFieldSignatureMethod,
+	signicat.MergeFieldURL,
+}
+
+var placeholderRegexp = regexp.MustCompile(`\{[A-Za-z0-9_-]+\}`)
+
+// Placeholder is a merge field found in a text.
+type Placeholder struct {
+	// Field is the merge field including the braces, eg. {document-title}.
+	Field string
+	// Offset is the byte offset of the placeholder in the text.
+	Offset int
+}
+
+// Parse returns the placeholders in text in the order they appear.
+func Parse(text string) []Placeholder {
+	var placeholders []Placeholder
+	for _, loc := range placeholderRegexp.FindAllStringIndex(text, -1) {
+		placeholders = append(placeholders, Placeholder{Field: text[loc[0]:loc[1]], Offset: loc[0]})
+	}
+
+	return placeholders
+}
+
+// ValidationError is returned for a placeholder which is not a known merge field.
+type ValidationError struct {
+	// Location describes where the text is, eg. signRequest.email[EN].text. Empty when validating a single text.
+	Location    string
+	Placeholder Placeholder
+	// Suggestion is the closest known merge field, if any is close enough to likely be a typo.
+	Suggestion string
+}
+
+func (e *ValidationError) Error() string {
+	msg := fmt.Sprintf("unknown merge field %s at offset %d", e.Placeholder.Field, e.Placeholder.Offset)
+	if e.Location != "" {
+		msg = e.Location + ": " + msg
+	}
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf(", did you mean %s?", e.Suggestion)
+	}
+
+	return msg
+}
+
+// ValidationErrors holds all the errors found when validating.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks that every placeholder in text is a known merge field or one of the custom fields. Custom fields can be given
+// with or without braces. Returns ValidationErrors if not.
+func Validate(text string, customFields ...string) error {
+	if errs := validate("", text, allowedFields(customFields)); len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// ValidateNotification validates the email and sms texts in n. The custom merge fields declared on the additional recipients of the
+// final receipt are allowed in the final receipt texts. Returns ValidationErrors if any text is invalid.
+func ValidateNotification(n *signicat.Notification) error {
+	if n == nil {
+		return nil
+	}
+
+	known := allowedFields(nil)
+	var errs ValidationErrors
+
+	if n.SignRequest != nil {
+		errs = append(errs, validateTexts(signicat.NotificationTypeSignRequest, n.SignRequest.Email, n.SignRequest.Sms, known)...)
+	}
+	if n.Reminder != nil {
+		errs = append(errs, validateTexts(signicat.NotificationTypeReminder, n.Reminder.Email, n.Reminder.Sms, known)...)
+	}
+	if n.SignatureReceipt != nil {
+		errs = append(errs, validateTexts(signicat.NotificationTypeSignatureReceipt, n.SignatureReceipt.Email, n.SignatureReceipt.Sms, known)...)
+	}
+	if n.FinalReceipt != nil {
+		var custom []string
+		for _, r := range n.FinalReceipt.AdditionalRecipients {
+			for field := range r.CustomMergeFields {
+				custom = append(custom, field)
+			}
+		}
+		errs = append(errs, validateTexts(signicat.NotificationTypeFinalReceipt, n.FinalReceipt.Email, n.FinalReceipt.Sms, allowedFields(custom))...)
+	}
+	if n.CanceledReceipt != nil {
+		errs = append(errs, validateTexts(signicat.NotificationTypeCanceledReceipt, n.CanceledReceipt.Email, n.CanceledReceipt.Sms, known)...)
+	}
+	if n.ExpiredReceipt != nil {
+		errs = append(errs, validateTexts(signicat.NotificationTypeExpiredReceipt, n.ExpiredReceipt.Email, n.ExpiredReceipt.Sms, known)...)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// Render replaces the placeholders in text with values, which are keyed by merge field, eg. signicat.MergeFieldDocumentTitle.
+// Returns an error if a placeholder in text has no value.
+func Render(text string, values map[string]string) (string, error) {
+	var missing []string
+	seen := make(map[string]bool)
+	for _, p := range Parse(text) {
+		if _, ok := values[p.Field]; !ok && !seen[p.Field] {
+			missing = append(missing, p.Field)
+			seen[p.Field] = true
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("no value for merge fields: %s", strings.Join(missing, ", "))
+	}
+
+	return signicat.RenderMergeFields(text, values), nil
+}
+
+// SampleValues returns merge field values taken from req, filling in plausible samples where req has nothing to offer. The values
+// can be used with Render to preview what a signer will receive.
+func SampleValues(req *signicat.CreateDocumentRequest) map[string]string {
+	now := time.Now()
+	values := map[string]string{
+		signicat.MergeFieldDocumentTitle:          "Sample document",
+		signicat.MergeFieldDocumentDescription:    "",
+		signicat.MergeFieldSignableDocumetTitles:  "Sample document",
+		signicat.MergeFieldReadOnlyDocumentTitles: "",
+		signicat.MergeFieldDeadline:               now.AddDate(0, 0, 30).Format("2006-01-02"),
+		signicat.MergeFieldSignedTime:             now.Format("15:04"),
+		signicat.MergeFieldSignedDate:             now.Format("2006-01-02"),
+		signicat.MergeFieldSignedName:             "Ola Nordmann",
+		signicat.MergeFieldSignatureMethod:        "BankID",
+		signicat.MergeFieldURL:                    SampleURL,
+	}
+
+	if req == nil {
+		return values
+	}
+
+	if req.Title != "" {
+		values[signicat.MergeFieldDocumentTitle] = req.Title
+		values[signicat.MergeFieldSignableDocumetTitles] = req.Title
+	}
+	values[signicat.MergeFieldDocumentDescription] = req.Description
+	if req.DataToSign != nil && req.DataToSign.Title != "" {
+		values[signicat.MergeFieldSignableDocumetTitles] = req.DataToSign.Title
+	}
+	if req.Advanced != nil && req.Advanced.TimeToLive != nil && req.Advanced.TimeToLive.Deadline != nil {
+		values[signicat.MergeFieldDeadline] = req.Advanced.TimeToLive.Deadline.Format("2006-01-02")
+	}
+	for _, signer := range req.Signers {
+		if signer.SignerInfo != nil && (signer.SignerInfo.FirstName != "" || signer.SignerInfo.LastName != "") {
+			values[signicat.MergeFieldSignedName] = strings.TrimSpace(signer.SignerInfo.FirstName + " " + signer.SignerInfo.LastName)
+			break
+		}
+	}
+
+	return values
+}
+
+func allowedFields(custom []string) map[string]bool {
+	allowed := make(map[string]bool, len(MergeFields)+len(custom))
+	for _, f := range MergeFields {
+		allowed[f] = true
+	}
+	for _, f := range custom {
+		if !strings.HasPrefix(f, "{") {
+			f = "{" + f + "}"
+		}
+		allowed[f] = true
+	}
+
+	return allowed
+}
+
+func validateTexts(notificationType string, emails []*signicat.Email, sms []*signicat.Sms, allowed map[string]bool) ValidationErrors {
+	var errs ValidationErrors
+	for _, e := range emails {
+		location := fmt.Sprintf("%s.email[%s]", notificationType, e.Language)
+		errs = append(errs, validate(location+".subject", e.Subject, allowed)...)
+		errs = append(errs, validate(location+".text", e.Text, allowed)...)
+	}
+	for _, s := range sms {
+		errs = append(errs, validate(fmt.Sprintf("%s.sms[%s].text", notificationType, s.Language), s.Text, allowed)...)
+	}
+
+	return errs
+}
+
+func validate(location, text string, allowed map[string]bool) ValidationErrors {
+	var errs ValidationErrors
+	for _, p := range Parse(text) {
+		if allowed[p.Field] {
+			continue
+		}
+		errs = append(errs, &ValidationError{Location: location, Placeholder: p, Suggestion: suggest(p.Field, allowed)})
+	}
+
+	return errs
+}
+
+// suggest returns the allowed field closest to field, if it is close enough to likely be a typo.
+func suggest(field string, allowed map[string]bool) string {
+	best, bestDistance := "", 3
+	for candidate := range allowed {
+		d := distance(field, candidate)
+		if d < bestDistance || (d == bestDistance && best != "" && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+
+	return best
+}
+
+// distance is the Damerau-Levenshtein (optimal string alignment) distance between a and b, counting a swap of two adjacent
+// characters as one edit.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(minInt(d[i-1][j]+1, d[i][j-1]+1), d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,111 @@
+package template
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/larwef/signicat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	placeholders := Parse("Sign {document-title} at {url}. {not a field}")
+	assert.Equal(t, []Placeholder{{Field: "{document-title}", Offset: 5}, {Field: "{url}", Offset: 25}}, placeholders)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("Sign {document-title} before {deadline}"))
+	assert.NoError(t, Validate("Hi {customer}", "customer"))
+	assert.NoError(t, Validate("Hi {customer}", "{customer}"))
+
+	err := Validate("Sign {documnet-title} at {link}")
+	assert.Error(t, err)
+
+	errs, ok := err.(ValidationErrors)
+	assert.True(t, ok)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "{documnet-title}", errs[0].Placeholder.Field)
+	assert.Equal(t, signicat.MergeFieldDocumentTitle, errs[0].Suggestion)
+	assert.Equal(t, "{link}", errs[1].Placeholder.Field)
+	assert.Equal(t, "", errs[1].Suggestion)
+	assert.Equal(t, "unknown merge field {documnet-title} at offset 5, did you mean {document-title}?", errs[0].Error())
+}
+
+func TestValidateNotification(t *testing.T) {
+	n := &signicat.Notification{
+		SignRequest: &signicat.SignRequest{
+			Email: []*signicat.Email{{Language: signicat.LanguageEnglish, Subject: "{document-title}", Text: "{url}"}},
+			Sms:   []*signicat.Sms{{Language: signicat.LanguageNorwegian, Text: "{deadlien}"}},
+		},
+		FinalReceipt: &signicat.FinalReceipt{
+			AdditionalRecipients: []*signicat.AdditionalRecipient{{Email: "a@example.com", CustomMergeFields: map[string]string{"{case-number}": "1"}}},
+			Email:                []*signicat.Email{{Language: signicat.LanguageEnglish, Text: "Case {case-number}"}},
+		},
+		CanceledReceipt: &signicat.CanceledReceipt{
+			Email: []*signicat.Email{{Language: signicat.LanguageEnglish, Text: "Case {case-number}"}},
+		},
+	}
+
+	err := ValidateNotification(n)
+	errs, ok := err.(ValidationErrors)
+	assert.True(t, ok)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "signRequest.sms[NO].text", errs[0].Location)
+	assert.Equal(t, signicat.MergeFieldDeadline, errs[0].Suggestion)
+	assert.Equal(t, "canceledReceipt.email[EN].text", errs[1].Location)
+
+	assert.NoError(t, ValidateNotification(nil))
+}
+
+func TestRender(t *testing.T) {
+	deadline := time.Date(2020, 6, 30, 0, 0, 0, 0, time.UTC)
+	values := SampleValues(&signicat.CreateDocumentRequest{
+		Title:    "Contract",
+		Signers:  []*signicat.SignerRequest{{SignerInfo: &signicat.SignerInfo{FirstName: "Kari", LastName: "Nordmann"}}},
+		Advanced: &signicat.Advanced{TimeToLive: &signicat.TimeToLive{Deadline: &deadline}},
+	})
+
+	text, err := Render("{signed-name}: sign {document-title} before {deadline} at {url}", values)
+	assert.NoError(t, err)
+	assert.Equal(t, "Kari Nordmann: sign Contract before 2020-06-30 at "+SampleURL, text)
+
+	_, err = Render("{custom} {custom}", values)
+	assert.EqualError(t, err, "no value for merge fields: {custom}")
+}
+
+func TestMeasureSms(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected SmsInfo
+	}{
+		{text: "", expected: SmsInfo{Encoding: EncodingGSM7}},
+		{text: "Hei, signer avtalen på Æøå", expected: SmsInfo{Encoding: EncodingGSM7, Length: 26, Segments: 1}},
+		{text: "Price: 10€", expected: SmsInfo{Encoding: EncodingGSM7, Length: 11, Segments: 1}},
+		{text: strings.Repeat("a", 160), expected: SmsInfo{Encoding: EncodingGSM7, Length: 160, Segments: 1}},
+		{text: strings.Repeat("a", 161), expected: SmsInfo{Encoding: EncodingGSM7, Length: 161, Segments: 2}},
+		{text: "Allekirjoita – kiitos", expected: SmsInfo{Encoding: EncodingUCS2, Length: 21, Segments: 1}},
+		{text: strings.Repeat("š", 71), expected: SmsInfo{Encoding: EncodingUCS2, Length: 71, Segments: 2}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, MeasureSms(test.text), test.text)
+	}
+}
+
+func TestCheckSms(t *testing.T) {
+	values := map[string]string{signicat.MergeFieldURL: SampleURL}
+	sms := []*signicat.Sms{
+		{Language: signicat.LanguageNorwegian, Text: "Signer her: {url}"},
+		{Language: signicat.LanguageEnglish, Text: strings.Repeat("Please sign. ", 20) + "{url}"},
+	}
+
+	infos, err := CheckSms(sms, values, 1)
+	assert.EqualError(t, err, "invalid sms: sms[EN]: 2 segments exceeds the limit of 1")
+	assert.Len(t, infos, 2)
+	assert.Equal(t, signicat.LanguageNorwegian, infos[0].Language)
+	assert.Equal(t, 1, infos[0].Segments)
+
+	_, err = CheckSms(sms, values, 0)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,193 @@
+package signicat
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/larwef/signicat/nin"
+	"github.com/larwef/signicat/orgno"
+	"github.com/larwef/signicat/preflight"
+)
+
+// Validator checks a CreateDocumentRequest locally, to catch requests Signicat would reject before they are sent. The zero value
+// checks that the required fields are set, and the national identity numbers and organization numbers of signers whose country
+// is known.
+type Validator struct {
+	// Preflight, if set, runs the checks in the preflight package with these options on the file in DataToSign.Base64Content.
+	// Files given as DataToSign.Content or DataToSign.FileID are not checked.
+	Preflight *preflight.Options
+	// Country is the country of the national identity numbers of signers whose country can't be told from their signature
+	// methods, eg. nin.Norway. Numbers are only checked when the country is known and supported by the nin package.
+	Country string
+}
+
+// The countries of the signature methods, used to tell which national identity numbers signers have.
+var signatureMethodCountries = map[string]string{
+	SignatureMethodNoBankIDMobile:     nin.Norway,
+	SignatureMethodNoBankIDNetCentric: nin.Norway,
+	SignatureMethodNoBuypass:          nin.Norway,
+	SignatureMethodSeBankID:           nin.Sweden,
+	SignatureMethodDkNemID:            nin.Denmark,
+	SignatureMethodFiTupas:            nin.Finland,
+	SignatureMethodFiMobiilivarmenne:  nin.Finland,
+	SignatureMethodFiEid:              nin.Finland,
+}
+
+// ValidationError is a field of a request which is invalid.
+type ValidationError struct {
+	// Field is the path of the field, eg. signers[0].externalSignerId.
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors holds all the errors found when validating.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks createReq, and returns ValidationErrors if it is invalid.
+func (v *Validator) Validate(createReq *CreateDocumentRequest) error {
+	var errs ValidationErrors
+	add := func(field, format string, a ...interface{}) {
+		errs = append(errs, &ValidationError{Field: field, Message: fmt.Sprintf(format, a...)})
+	}
+
+	if createReq.Title == "" {
+		add("title", "is required")
+	}
+	if createReq.ContactDetails == nil || createReq.ContactDetails.Email == "" {
+		add("contactDetails.email", "is required")
+	}
+
+	if len(createReq.Signers) == 0 {
+		add("signers", "at least one signer is required")
+	}
+	seen := make(map[string]bool)
+	for i, signer := range createReq.Signers {
+		field := fmt.Sprintf("signers[%d]", i)
+		if signer.ExternalSignerID == "" {
+			add(field+".externalSignerId", "is required")
+		} else if seen[signer.ExternalSignerID] {
+			add(field+".externalSignerId", "%s is used by more than one signer", signer.ExternalSignerID)
+		}
+		seen[signer.ExternalSignerID] = true
+
+		if signer.RedirectSettings == nil {
+			add(field+".redirectSettings", "is required")
+		}
+		if signer.SignatureType == nil || signer.SignatureType.Mechanism == "" {
+			add(field+".signatureType.mechanism", "is required")
+		}
+
+		country := v.country(signer)
+		if signer.SignerInfo != nil && signer.SignerInfo.SocialSecurityNumber != "" {
+			if err := validateNIN(country, signer.SignerInfo.SocialSecurityNumber); err != nil {
+				add(field+".signerInfo.socialSecurityNumber", "%v", err)
+			}
+		}
+		if signer.Authentication != nil && signer.Authentication.SocialSecurityNumber != "" {
+			if err := validateNIN(country, signer.Authentication.SocialSecurityNumber); err != nil {
+				add(field+".authentication.socialSecurityNumber", "%v", err)
+			}
+		}
+		if signer.SignerInfo != nil && signer.SignerInfo.OrganizationInfo != nil {
+			if err := validateOrgNo(signer.SignerInfo.OrganizationInfo); err != nil {
+				add(field+".signerInfo.organizationInfo.orgNo", "%v", err)
+			}
+		}
+	}
+
+	errs = append(errs, v.validateDataToSign(createReq.DataToSign)...)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// country returns the country of the signature methods of signer, or Country if they aren't all of the same country.
+func (v *Validator) country(signer *SignerRequest) string {
+	country := ""
+	if signer.SignatureType != nil {
+		for _, method := range signer.SignatureType.SignatureMethods {
+			c, ok := signatureMethodCountries[method]
+			if !ok || (country != "" && c != country) {
+				return v.Country
+			}
+			country = c
+		}
+	}
+	if country == "" {
+		return v.Country
+	}
+
+	return country
+}
+
+// validateNIN checks a national identity number, if the country is supported.
+func validateNIN(country, number string) error {
+	if !nin.Supported(country) {
+		return nil
+	}
+
+	return nin.Validate(country, number)
+}
+
+// validateOrgNo checks the organization number of org, if its country is supported.
+func validateOrgNo(org *OrganizationInfo) error {
+	if org.OrgNo == "" || !orgno.Supported(org.CountryCode) {
+		return nil
+	}
+
+	return orgno.Validate(org.CountryCode, org.OrgNo)
+}
+
+func (v *Validator) validateDataToSign(dataToSign *DataToSign) ValidationErrors {
+	if dataToSign == nil {
+		return ValidationErrors{{Field: "dataToSign", Message: "is required"}}
+	}
+
+	var errs ValidationErrors
+	if dataToSign.FileName == "" {
+		errs = append(errs, &ValidationError{Field: "dataToSign.fileName", Message: "is required"})
+	}
+
+	sources := 0
+	for _, set := range []bool{dataToSign.Base64Content != "", dataToSign.FileID != "", dataToSign.Content != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		errs = append(errs, &ValidationError{Field: "dataToSign", Message: "exactly one of base64Content, fileId and Content must be set"})
+	}
+
+	if dataToSign.Base64Content == "" {
+		return errs
+	}
+	data, err := base64.StdEncoding.DecodeString(dataToSign.Base64Content)
+	if err != nil {
+		return append(errs, &ValidationError{Field: "dataToSign.base64Content", Message: err.Error()})
+	}
+
+	if v.Preflight != nil {
+		for _, issue := range preflight.Check(data, dataToSign.FileName, v.Preflight).Errors() {
+			errs = append(errs, &ValidationError{Field: "dataToSign.base64Content", Message: issue.Message})
+		}
+	}
+
+	return errs
+}
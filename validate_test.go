@@ -0,0 +1,105 @@
+package signicat
+
+import (
+	"encoding/base64"
+	"github.com/larwef/signicat/preflight"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func validCreateDocumentRequest() *CreateDocumentRequest {
+	return &CreateDocumentRequest{
+		Title: "Contract",
+		Signers: []*SignerRequest{{
+			ExternalSignerID: "someSigner",
+			RedirectSettings: &RedirectSettings{RedirectMode: RedirectModeDoNotRedirect},
+			SignatureType:    &SignatureType{Mechanism: MechanismsPkiSignature},
+		}},
+		DataToSign:     &DataToSign{FileName: "contract.txt", Base64Content: base64.StdEncoding.EncodeToString([]byte("Contract"))},
+		ContactDetails: &ContactDetails{Email: "contact@example.com"},
+		ExternalID:     "someExternalId",
+	}
+}
+
+func TestValidator_Validate(t *testing.T) {
+	v := &Validator{}
+	assert.NoError(t, v.Validate(validCreateDocumentRequest()))
+
+	createReq := validCreateDocumentRequest()
+	createReq.Title = ""
+	createReq.Signers = append(createReq.Signers, &SignerRequest{ExternalSignerID: "someSigner"})
+	createReq.DataToSign.FileID = "someFileId"
+
+	err := v.Validate(createReq)
+	assert.Error(t, err)
+	errs, ok := err.(ValidationErrors)
+	assert.True(t, ok)
+
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{
+		"title",
+		"signers[1].externalSignerId",
+		"signers[1].redirectSettings",
+		"signers[1].signatureType.mechanism",
+		"dataToSign",
+	}, fields)
+}
+
+func TestValidator_Validate_Preflight(t *testing.T) {
+	createReq := validCreateDocumentRequest()
+	createReq.DataToSign.Base64Content = base64.StdEncoding.EncodeToString([]byte{0, 1, 2, 3})
+
+	// Without preflight the content isn't checked.
+	v := &Validator{}
+	assert.NoError(t, v.Validate(createReq))
+
+	v.Preflight = &preflight.Options{}
+	err := v.Validate(createReq)
+	assert.EqualError(t, err, "dataToSign.base64Content: the file is not a PDF, DOCX, XML or text file")
+
+	createReq.DataToSign.Base64Content = "not base64"
+	assert.Error(t, v.Validate(createReq))
+}
+
+func TestValidator_Validate_NationalIdentityNumber(t *testing.T) {
+	createReq := validCreateDocumentRequest()
+	createReq.Signers[0].SignatureType.SignatureMethods = []string{SignatureMethodNoBankIDMobile, SignatureMethodNoBankIDNetCentric}
+	createReq.Signers[0].SignerInfo = &SignerInfo{SocialSecurityNumber: "01019012480"}
+	createReq.Signers[0].Authentication = &Authentication{SocialSecurityNumber: "01019012481"}
+
+	v := &Validator{}
+	err := v.Validate(createReq)
+	errs, ok := err.(ValidationErrors)
+	assert.True(t, ok)
+	assert.Equal(t, ValidationErrors{
+		{Field: "signers[0].authentication.socialSecurityNumber", Message: "invalid fødselsnummer: check digits are wrong"},
+	}, errs)
+
+	// The country can't be told from the signature methods, so Country is used.
+	createReq.Signers[0].Authentication = nil
+	createReq.Signers[0].SignatureType.SignatureMethods = []string{SignatureMethodNoBankIDMobile, SignatureMethodSeBankID}
+	assert.NoError(t, v.Validate(createReq))
+
+	v.Country = "SE"
+	err = v.Validate(createReq)
+	assert.EqualError(t, err, "signers[0].signerInfo.socialSecurityNumber: invalid personnummer: must be 10 or 12 digits")
+}
+
+func TestValidator_Validate_OrganizationNumber(t *testing.T) {
+	createReq := validCreateDocumentRequest()
+	createReq.Signers[0].SignerInfo = &SignerInfo{OrganizationInfo: &OrganizationInfo{OrgNo: "974 760 673", CountryCode: "NO"}}
+
+	v := &Validator{}
+	assert.NoError(t, v.Validate(createReq))
+
+	createReq.Signers[0].SignerInfo.OrganizationInfo.OrgNo = "974760674"
+	err := v.Validate(createReq)
+	assert.EqualError(t, err, "signers[0].signerInfo.organizationInfo.orgNo: invalid organisasjonsnummer: check digit is wrong")
+
+	// Numbers of other countries aren't checked.
+	createReq.Signers[0].SignerInfo.OrganizationInfo.CountryCode = "IS"
+	assert.NoError(t, v.Validate(createReq))
+}